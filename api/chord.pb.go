@@ -33,7 +33,7 @@ func (m *Void) Reset()         { *m = Void{} }
 func (m *Void) String() string { return proto.CompactTextString(m) }
 func (*Void) ProtoMessage()    {}
 func (*Void) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chord_60da2256c3abf9d6, []int{0}
+	return fileDescriptor_chord_4c2dc8f28e7271ff, []int{0}
 }
 func (m *Void) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Void.Unmarshal(m, b)
@@ -65,7 +65,7 @@ func (m *Id) Reset()         { *m = Id{} }
 func (m *Id) String() string { return proto.CompactTextString(m) }
 func (*Id) ProtoMessage()    {}
 func (*Id) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chord_60da2256c3abf9d6, []int{1}
+	return fileDescriptor_chord_4c2dc8f28e7271ff, []int{1}
 }
 func (m *Id) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Id.Unmarshal(m, b)
@@ -110,7 +110,7 @@ func (m *NodeId) Reset()         { *m = NodeId{} }
 func (m *NodeId) String() string { return proto.CompactTextString(m) }
 func (*NodeId) ProtoMessage()    {}
 func (*NodeId) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chord_60da2256c3abf9d6, []int{2}
+	return fileDescriptor_chord_4c2dc8f28e7271ff, []int{2}
 }
 func (m *NodeId) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_NodeId.Unmarshal(m, b)
@@ -138,9 +138,13 @@ func (m *NodeId) GetVal() []byte {
 }
 
 type ContactInfo struct {
-	Address              string   `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
-	Id                   *NodeId  `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
-	Payload              []byte   `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`
+	Address string  `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Id      *NodeId `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	// payload holds the node's metadata as JSON.
+	Payload []byte `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`
+	// Peers from before versioning leave both unset.
+	Version              uint32   `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
+	Capabilities         uint64   `protobuf:"varint,5,opt,name=capabilities,proto3" json:"capabilities,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -150,7 +154,7 @@ func (m *ContactInfo) Reset()         { *m = ContactInfo{} }
 func (m *ContactInfo) String() string { return proto.CompactTextString(m) }
 func (*ContactInfo) ProtoMessage()    {}
 func (*ContactInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chord_60da2256c3abf9d6, []int{3}
+	return fileDescriptor_chord_4c2dc8f28e7271ff, []int{3}
 }
 func (m *ContactInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ContactInfo.Unmarshal(m, b)
@@ -191,213 +195,2070 @@ func (m *ContactInfo) GetPayload() []byte {
 	return nil
 }
 
+func (m *ContactInfo) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *ContactInfo) GetCapabilities() uint64 {
+	if m != nil {
+		return m.Capabilities
+	}
+	return 0
+}
+
+type ContactInfoList struct {
+	Contacts             []*ContactInfo `protobuf:"bytes,1,rep,name=contacts,proto3" json:"contacts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *ContactInfoList) Reset()         { *m = ContactInfoList{} }
+func (m *ContactInfoList) String() string { return proto.CompactTextString(m) }
+func (*ContactInfoList) ProtoMessage()    {}
+func (*ContactInfoList) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chord_4c2dc8f28e7271ff, []int{4}
+}
+func (m *ContactInfoList) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ContactInfoList.Unmarshal(m, b)
+}
+func (m *ContactInfoList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ContactInfoList.Marshal(b, m, deterministic)
+}
+func (dst *ContactInfoList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ContactInfoList.Merge(dst, src)
+}
+func (m *ContactInfoList) XXX_Size() int {
+	return xxx_messageInfo_ContactInfoList.Size(m)
+}
+func (m *ContactInfoList) XXX_DiscardUnknown() {
+	xxx_messageInfo_ContactInfoList.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ContactInfoList proto.InternalMessageInfo
+
+func (m *ContactInfoList) GetContacts() []*ContactInfo {
+	if m != nil {
+		return m.Contacts
+	}
+	return nil
+}
+
+type Key struct {
+	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Key) Reset()         { *m = Key{} }
+func (m *Key) String() string { return proto.CompactTextString(m) }
+func (*Key) ProtoMessage()    {}
+func (*Key) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chord_4c2dc8f28e7271ff, []int{5}
+}
+func (m *Key) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Key.Unmarshal(m, b)
+}
+func (m *Key) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Key.Marshal(b, m, deterministic)
+}
+func (dst *Key) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Key.Merge(dst, src)
+}
+func (m *Key) XXX_Size() int {
+	return xxx_messageInfo_Key.Size(m)
+}
+func (m *Key) XXX_DiscardUnknown() {
+	xxx_messageInfo_Key.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Key proto.InternalMessageInfo
+
+func (m *Key) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+type Item struct {
+	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value                []byte   `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Version              uint64   `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Item) Reset()         { *m = Item{} }
+func (m *Item) String() string { return proto.CompactTextString(m) }
+func (*Item) ProtoMessage()    {}
+func (*Item) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chord_4c2dc8f28e7271ff, []int{6}
+}
+func (m *Item) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Item.Unmarshal(m, b)
+}
+func (m *Item) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Item.Marshal(b, m, deterministic)
+}
+func (dst *Item) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Item.Merge(dst, src)
+}
+func (m *Item) XXX_Size() int {
+	return xxx_messageInfo_Item.Size(m)
+}
+func (m *Item) XXX_DiscardUnknown() {
+	xxx_messageInfo_Item.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Item proto.InternalMessageInfo
+
+func (m *Item) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *Item) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *Item) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+type Hint struct {
+	Target               *ContactInfo `protobuf:"bytes,1,opt,name=target,proto3" json:"target,omitempty"`
+	Item                 *Item        `protobuf:"bytes,2,opt,name=item,proto3" json:"item,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *Hint) Reset()         { *m = Hint{} }
+func (m *Hint) String() string { return proto.CompactTextString(m) }
+func (*Hint) ProtoMessage()    {}
+func (*Hint) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chord_4c2dc8f28e7271ff, []int{7}
+}
+func (m *Hint) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Hint.Unmarshal(m, b)
+}
+func (m *Hint) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Hint.Marshal(b, m, deterministic)
+}
+func (dst *Hint) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Hint.Merge(dst, src)
+}
+func (m *Hint) XXX_Size() int {
+	return xxx_messageInfo_Hint.Size(m)
+}
+func (m *Hint) XXX_DiscardUnknown() {
+	xxx_messageInfo_Hint.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Hint proto.InternalMessageInfo
+
+func (m *Hint) GetTarget() *ContactInfo {
+	if m != nil {
+		return m.Target
+	}
+	return nil
+}
+
+func (m *Hint) GetItem() *Item {
+	if m != nil {
+		return m.Item
+	}
+	return nil
+}
+
+type LoadReport struct {
+	Id                   *NodeId  `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Arc                  float64  `protobuf:"fixed64,2,opt,name=arc,proto3" json:"arc,omitempty"`
+	RequestRate          float64  `protobuf:"fixed64,3,opt,name=request_rate,json=requestRate,proto3" json:"request_rate,omitempty"`
+	Keys                 uint64   `protobuf:"varint,4,opt,name=keys,proto3" json:"keys,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LoadReport) Reset()         { *m = LoadReport{} }
+func (m *LoadReport) String() string { return proto.CompactTextString(m) }
+func (*LoadReport) ProtoMessage()    {}
+func (*LoadReport) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chord_4c2dc8f28e7271ff, []int{8}
+}
+func (m *LoadReport) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LoadReport.Unmarshal(m, b)
+}
+func (m *LoadReport) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LoadReport.Marshal(b, m, deterministic)
+}
+func (dst *LoadReport) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LoadReport.Merge(dst, src)
+}
+func (m *LoadReport) XXX_Size() int {
+	return xxx_messageInfo_LoadReport.Size(m)
+}
+func (m *LoadReport) XXX_DiscardUnknown() {
+	xxx_messageInfo_LoadReport.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LoadReport proto.InternalMessageInfo
+
+func (m *LoadReport) GetId() *NodeId {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+func (m *LoadReport) GetArc() float64 {
+	if m != nil {
+		return m.Arc
+	}
+	return 0
+}
+
+func (m *LoadReport) GetRequestRate() float64 {
+	if m != nil {
+		return m.RequestRate
+	}
+	return 0
+}
+
+func (m *LoadReport) GetKeys() uint64 {
+	if m != nil {
+		return m.Keys
+	}
+	return 0
+}
+
+type IdList struct {
+	Ids                  []*NodeId `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *IdList) Reset()         { *m = IdList{} }
+func (m *IdList) String() string { return proto.CompactTextString(m) }
+func (*IdList) ProtoMessage()    {}
+func (*IdList) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chord_4c2dc8f28e7271ff, []int{9}
+}
+func (m *IdList) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IdList.Unmarshal(m, b)
+}
+func (m *IdList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IdList.Marshal(b, m, deterministic)
+}
+func (dst *IdList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IdList.Merge(dst, src)
+}
+func (m *IdList) XXX_Size() int {
+	return xxx_messageInfo_IdList.Size(m)
+}
+func (m *IdList) XXX_DiscardUnknown() {
+	xxx_messageInfo_IdList.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IdList proto.InternalMessageInfo
+
+func (m *IdList) GetIds() []*NodeId {
+	if m != nil {
+		return m.Ids
+	}
+	return nil
+}
+
+type Lookup struct {
+	Id                   *NodeId      `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Successor            *ContactInfo `protobuf:"bytes,2,opt,name=successor,proto3" json:"successor,omitempty"`
+	Found                bool         `protobuf:"varint,3,opt,name=found,proto3" json:"found,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *Lookup) Reset()         { *m = Lookup{} }
+func (m *Lookup) String() string { return proto.CompactTextString(m) }
+func (*Lookup) ProtoMessage()    {}
+func (*Lookup) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chord_4c2dc8f28e7271ff, []int{10}
+}
+func (m *Lookup) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Lookup.Unmarshal(m, b)
+}
+func (m *Lookup) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Lookup.Marshal(b, m, deterministic)
+}
+func (dst *Lookup) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Lookup.Merge(dst, src)
+}
+func (m *Lookup) XXX_Size() int {
+	return xxx_messageInfo_Lookup.Size(m)
+}
+func (m *Lookup) XXX_DiscardUnknown() {
+	xxx_messageInfo_Lookup.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Lookup proto.InternalMessageInfo
+
+func (m *Lookup) GetId() *NodeId {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+func (m *Lookup) GetSuccessor() *ContactInfo {
+	if m != nil {
+		return m.Successor
+	}
+	return nil
+}
+
+func (m *Lookup) GetFound() bool {
+	if m != nil {
+		return m.Found
+	}
+	return false
+}
+
+type Heartbeat struct {
+	Info                 *ContactInfo `protobuf:"bytes,1,opt,name=info,proto3" json:"info,omitempty"`
+	Count                uint64       `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *Heartbeat) Reset()         { *m = Heartbeat{} }
+func (m *Heartbeat) String() string { return proto.CompactTextString(m) }
+func (*Heartbeat) ProtoMessage()    {}
+func (*Heartbeat) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chord_4c2dc8f28e7271ff, []int{11}
+}
+func (m *Heartbeat) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Heartbeat.Unmarshal(m, b)
+}
+func (m *Heartbeat) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Heartbeat.Marshal(b, m, deterministic)
+}
+func (dst *Heartbeat) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Heartbeat.Merge(dst, src)
+}
+func (m *Heartbeat) XXX_Size() int {
+	return xxx_messageInfo_Heartbeat.Size(m)
+}
+func (m *Heartbeat) XXX_DiscardUnknown() {
+	xxx_messageInfo_Heartbeat.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Heartbeat proto.InternalMessageInfo
+
+func (m *Heartbeat) GetInfo() *ContactInfo {
+	if m != nil {
+		return m.Info
+	}
+	return nil
+}
+
+func (m *Heartbeat) GetCount() uint64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+type HeartbeatList struct {
+	Heartbeats           []*Heartbeat `protobuf:"bytes,1,rep,name=heartbeats,proto3" json:"heartbeats,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *HeartbeatList) Reset()         { *m = HeartbeatList{} }
+func (m *HeartbeatList) String() string { return proto.CompactTextString(m) }
+func (*HeartbeatList) ProtoMessage()    {}
+func (*HeartbeatList) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chord_4c2dc8f28e7271ff, []int{12}
+}
+func (m *HeartbeatList) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HeartbeatList.Unmarshal(m, b)
+}
+func (m *HeartbeatList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HeartbeatList.Marshal(b, m, deterministic)
+}
+func (dst *HeartbeatList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HeartbeatList.Merge(dst, src)
+}
+func (m *HeartbeatList) XXX_Size() int {
+	return xxx_messageInfo_HeartbeatList.Size(m)
+}
+func (m *HeartbeatList) XXX_DiscardUnknown() {
+	xxx_messageInfo_HeartbeatList.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HeartbeatList proto.InternalMessageInfo
+
+func (m *HeartbeatList) GetHeartbeats() []*Heartbeat {
+	if m != nil {
+		return m.Heartbeats
+	}
+	return nil
+}
+
+type PredecessorReply struct {
+	HasPredecessor       bool         `protobuf:"varint,1,opt,name=has_predecessor,json=hasPredecessor,proto3" json:"has_predecessor,omitempty"`
+	Predecessor          *ContactInfo `protobuf:"bytes,2,opt,name=predecessor,proto3" json:"predecessor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *PredecessorReply) Reset()         { *m = PredecessorReply{} }
+func (m *PredecessorReply) String() string { return proto.CompactTextString(m) }
+func (*PredecessorReply) ProtoMessage()    {}
+func (*PredecessorReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chord_4c2dc8f28e7271ff, []int{13}
+}
+func (m *PredecessorReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PredecessorReply.Unmarshal(m, b)
+}
+func (m *PredecessorReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PredecessorReply.Marshal(b, m, deterministic)
+}
+func (dst *PredecessorReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PredecessorReply.Merge(dst, src)
+}
+func (m *PredecessorReply) XXX_Size() int {
+	return xxx_messageInfo_PredecessorReply.Size(m)
+}
+func (m *PredecessorReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_PredecessorReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PredecessorReply proto.InternalMessageInfo
+
+func (m *PredecessorReply) GetHasPredecessor() bool {
+	if m != nil {
+		return m.HasPredecessor
+	}
+	return false
+}
+
+func (m *PredecessorReply) GetPredecessor() *ContactInfo {
+	if m != nil {
+		return m.Predecessor
+	}
+	return nil
+}
+
+type FetchReply struct {
+	Found                bool     `protobuf:"varint,1,opt,name=found,proto3" json:"found,omitempty"`
+	Item                 *Item    `protobuf:"bytes,2,opt,name=item,proto3" json:"item,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FetchReply) Reset()         { *m = FetchReply{} }
+func (m *FetchReply) String() string { return proto.CompactTextString(m) }
+func (*FetchReply) ProtoMessage()    {}
+func (*FetchReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chord_4c2dc8f28e7271ff, []int{14}
+}
+func (m *FetchReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FetchReply.Unmarshal(m, b)
+}
+func (m *FetchReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FetchReply.Marshal(b, m, deterministic)
+}
+func (dst *FetchReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FetchReply.Merge(dst, src)
+}
+func (m *FetchReply) XXX_Size() int {
+	return xxx_messageInfo_FetchReply.Size(m)
+}
+func (m *FetchReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_FetchReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FetchReply proto.InternalMessageInfo
+
+func (m *FetchReply) GetFound() bool {
+	if m != nil {
+		return m.Found
+	}
+	return false
+}
+
+func (m *FetchReply) GetItem() *Item {
+	if m != nil {
+		return m.Item
+	}
+	return nil
+}
+
+// NeighbourUpdate is sent on a watch whenever the node's predecessor or
+// successor list changes.
+type NeighbourUpdate struct {
+	Node                 *ContactInfo   `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	HasPredecessor       bool           `protobuf:"varint,2,opt,name=has_predecessor,json=hasPredecessor,proto3" json:"has_predecessor,omitempty"`
+	Predecessor          *ContactInfo   `protobuf:"bytes,3,opt,name=predecessor,proto3" json:"predecessor,omitempty"`
+	Successors           []*ContactInfo `protobuf:"bytes,4,rep,name=successors,proto3" json:"successors,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *NeighbourUpdate) Reset()         { *m = NeighbourUpdate{} }
+func (m *NeighbourUpdate) String() string { return proto.CompactTextString(m) }
+func (*NeighbourUpdate) ProtoMessage()    {}
+func (*NeighbourUpdate) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chord_4c2dc8f28e7271ff, []int{15}
+}
+func (m *NeighbourUpdate) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NeighbourUpdate.Unmarshal(m, b)
+}
+func (m *NeighbourUpdate) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NeighbourUpdate.Marshal(b, m, deterministic)
+}
+func (dst *NeighbourUpdate) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NeighbourUpdate.Merge(dst, src)
+}
+func (m *NeighbourUpdate) XXX_Size() int {
+	return xxx_messageInfo_NeighbourUpdate.Size(m)
+}
+func (m *NeighbourUpdate) XXX_DiscardUnknown() {
+	xxx_messageInfo_NeighbourUpdate.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NeighbourUpdate proto.InternalMessageInfo
+
+func (m *NeighbourUpdate) GetNode() *ContactInfo {
+	if m != nil {
+		return m.Node
+	}
+	return nil
+}
+
+func (m *NeighbourUpdate) GetHasPredecessor() bool {
+	if m != nil {
+		return m.HasPredecessor
+	}
+	return false
+}
+
+func (m *NeighbourUpdate) GetPredecessor() *ContactInfo {
+	if m != nil {
+		return m.Predecessor
+	}
+	return nil
+}
+
+func (m *NeighbourUpdate) GetSuccessors() []*ContactInfo {
+	if m != nil {
+		return m.Successors
+	}
+	return nil
+}
+
+// NeighboursRequest asks for a node's neighbours, notifying it of the
+// sender first when notify is set.
+type NeighboursRequest struct {
+	Notify               *ContactInfo `protobuf:"bytes,1,opt,name=notify,proto3" json:"notify,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *NeighboursRequest) Reset()         { *m = NeighboursRequest{} }
+func (m *NeighboursRequest) String() string { return proto.CompactTextString(m) }
+func (*NeighboursRequest) ProtoMessage()    {}
+func (*NeighboursRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chord_4c2dc8f28e7271ff, []int{16}
+}
+func (m *NeighboursRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NeighboursRequest.Unmarshal(m, b)
+}
+func (m *NeighboursRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NeighboursRequest.Marshal(b, m, deterministic)
+}
+func (dst *NeighboursRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NeighboursRequest.Merge(dst, src)
+}
+func (m *NeighboursRequest) XXX_Size() int {
+	return xxx_messageInfo_NeighboursRequest.Size(m)
+}
+func (m *NeighboursRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_NeighboursRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NeighboursRequest proto.InternalMessageInfo
+
+func (m *NeighboursRequest) GetNotify() *ContactInfo {
+	if m != nil {
+		return m.Notify
+	}
+	return nil
+}
+
+// Message is an application message routed to the node responsible for key.
+type Message struct {
+	Key                  *NodeId      `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Payload              []byte       `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+	Origin               *ContactInfo `protobuf:"bytes,3,opt,name=origin,proto3" json:"origin,omitempty"`
+	Hops                 uint32       `protobuf:"varint,4,opt,name=hops,proto3" json:"hops,omitempty"`
+	Final                bool         `protobuf:"varint,5,opt,name=final,proto3" json:"final,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *Message) Reset()         { *m = Message{} }
+func (m *Message) String() string { return proto.CompactTextString(m) }
+func (*Message) ProtoMessage()    {}
+func (*Message) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chord_4c2dc8f28e7271ff, []int{17}
+}
+func (m *Message) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Message.Unmarshal(m, b)
+}
+func (m *Message) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Message.Marshal(b, m, deterministic)
+}
+func (dst *Message) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Message.Merge(dst, src)
+}
+func (m *Message) XXX_Size() int {
+	return xxx_messageInfo_Message.Size(m)
+}
+func (m *Message) XXX_DiscardUnknown() {
+	xxx_messageInfo_Message.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Message proto.InternalMessageInfo
+
+func (m *Message) GetKey() *NodeId {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *Message) GetPayload() []byte {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func (m *Message) GetOrigin() *ContactInfo {
+	if m != nil {
+		return m.Origin
+	}
+	return nil
+}
+
+func (m *Message) GetHops() uint32 {
+	if m != nil {
+		return m.Hops
+	}
+	return 0
+}
+
+func (m *Message) GetFinal() bool {
+	if m != nil {
+		return m.Final
+	}
+	return false
+}
+
+type Reply struct {
+	Payload              []byte       `protobuf:"bytes,1,opt,name=payload,proto3" json:"payload,omitempty"`
+	Node                 *ContactInfo `protobuf:"bytes,2,opt,name=node,proto3" json:"node,omitempty"`
+	Hops                 uint32       `protobuf:"varint,3,opt,name=hops,proto3" json:"hops,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *Reply) Reset()         { *m = Reply{} }
+func (m *Reply) String() string { return proto.CompactTextString(m) }
+func (*Reply) ProtoMessage()    {}
+func (*Reply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chord_4c2dc8f28e7271ff, []int{18}
+}
+func (m *Reply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Reply.Unmarshal(m, b)
+}
+func (m *Reply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Reply.Marshal(b, m, deterministic)
+}
+func (dst *Reply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Reply.Merge(dst, src)
+}
+func (m *Reply) XXX_Size() int {
+	return xxx_messageInfo_Reply.Size(m)
+}
+func (m *Reply) XXX_DiscardUnknown() {
+	xxx_messageInfo_Reply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Reply proto.InternalMessageInfo
+
+func (m *Reply) GetPayload() []byte {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func (m *Reply) GetNode() *ContactInfo {
+	if m != nil {
+		return m.Node
+	}
+	return nil
+}
+
+func (m *Reply) GetHops() uint32 {
+	if m != nil {
+		return m.Hops
+	}
+	return 0
+}
+
+// BroadcastMessage is passed on by its receiver in the arc up to limit.
+type BroadcastMessage struct {
+	Id                   string       `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Payload              []byte       `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+	Origin               *ContactInfo `protobuf:"bytes,3,opt,name=origin,proto3" json:"origin,omitempty"`
+	Limit                *NodeId      `protobuf:"bytes,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	Ack                  bool         `protobuf:"varint,5,opt,name=ack,proto3" json:"ack,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *BroadcastMessage) Reset()         { *m = BroadcastMessage{} }
+func (m *BroadcastMessage) String() string { return proto.CompactTextString(m) }
+func (*BroadcastMessage) ProtoMessage()    {}
+func (*BroadcastMessage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chord_4c2dc8f28e7271ff, []int{19}
+}
+func (m *BroadcastMessage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BroadcastMessage.Unmarshal(m, b)
+}
+func (m *BroadcastMessage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BroadcastMessage.Marshal(b, m, deterministic)
+}
+func (dst *BroadcastMessage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BroadcastMessage.Merge(dst, src)
+}
+func (m *BroadcastMessage) XXX_Size() int {
+	return xxx_messageInfo_BroadcastMessage.Size(m)
+}
+func (m *BroadcastMessage) XXX_DiscardUnknown() {
+	xxx_messageInfo_BroadcastMessage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BroadcastMessage proto.InternalMessageInfo
+
+func (m *BroadcastMessage) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *BroadcastMessage) GetPayload() []byte {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func (m *BroadcastMessage) GetOrigin() *ContactInfo {
+	if m != nil {
+		return m.Origin
+	}
+	return nil
+}
+
+func (m *BroadcastMessage) GetLimit() *NodeId {
+	if m != nil {
+		return m.Limit
+	}
+	return nil
+}
+
+func (m *BroadcastMessage) GetAck() bool {
+	if m != nil {
+		return m.Ack
+	}
+	return false
+}
+
+type BroadcastAck struct {
+	Delivered            uint32         `protobuf:"varint,1,opt,name=delivered,proto3" json:"delivered,omitempty"`
+	Unreachable          []*ContactInfo `protobuf:"bytes,2,rep,name=unreachable,proto3" json:"unreachable,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *BroadcastAck) Reset()         { *m = BroadcastAck{} }
+func (m *BroadcastAck) String() string { return proto.CompactTextString(m) }
+func (*BroadcastAck) ProtoMessage()    {}
+func (*BroadcastAck) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chord_4c2dc8f28e7271ff, []int{20}
+}
+func (m *BroadcastAck) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BroadcastAck.Unmarshal(m, b)
+}
+func (m *BroadcastAck) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BroadcastAck.Marshal(b, m, deterministic)
+}
+func (dst *BroadcastAck) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BroadcastAck.Merge(dst, src)
+}
+func (m *BroadcastAck) XXX_Size() int {
+	return xxx_messageInfo_BroadcastAck.Size(m)
+}
+func (m *BroadcastAck) XXX_DiscardUnknown() {
+	xxx_messageInfo_BroadcastAck.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BroadcastAck proto.InternalMessageInfo
+
+func (m *BroadcastAck) GetDelivered() uint32 {
+	if m != nil {
+		return m.Delivered
+	}
+	return 0
+}
+
+func (m *BroadcastAck) GetUnreachable() []*ContactInfo {
+	if m != nil {
+		return m.Unreachable
+	}
+	return nil
+}
+
+// TopicJoin asks a node to take child into a topic's tree.
+type TopicJoin struct {
+	Topic                string       `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
+	Child                *ContactInfo `protobuf:"bytes,2,opt,name=child,proto3" json:"child,omitempty"`
+	Final                bool         `protobuf:"varint,3,opt,name=final,proto3" json:"final,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *TopicJoin) Reset()         { *m = TopicJoin{} }
+func (m *TopicJoin) String() string { return proto.CompactTextString(m) }
+func (*TopicJoin) ProtoMessage()    {}
+func (*TopicJoin) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chord_4c2dc8f28e7271ff, []int{21}
+}
+func (m *TopicJoin) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TopicJoin.Unmarshal(m, b)
+}
+func (m *TopicJoin) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TopicJoin.Marshal(b, m, deterministic)
+}
+func (dst *TopicJoin) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TopicJoin.Merge(dst, src)
+}
+func (m *TopicJoin) XXX_Size() int {
+	return xxx_messageInfo_TopicJoin.Size(m)
+}
+func (m *TopicJoin) XXX_DiscardUnknown() {
+	xxx_messageInfo_TopicJoin.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TopicJoin proto.InternalMessageInfo
+
+func (m *TopicJoin) GetTopic() string {
+	if m != nil {
+		return m.Topic
+	}
+	return ""
+}
+
+func (m *TopicJoin) GetChild() *ContactInfo {
+	if m != nil {
+		return m.Child
+	}
+	return nil
+}
+
+func (m *TopicJoin) GetFinal() bool {
+	if m != nil {
+		return m.Final
+	}
+	return false
+}
+
+type TopicMessage struct {
+	Id                   string       `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Topic                string       `protobuf:"bytes,2,opt,name=topic,proto3" json:"topic,omitempty"`
+	Payload              []byte       `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`
+	Publisher            *ContactInfo `protobuf:"bytes,4,opt,name=publisher,proto3" json:"publisher,omitempty"`
+	Hops                 uint32       `protobuf:"varint,5,opt,name=hops,proto3" json:"hops,omitempty"`
+	Final                bool         `protobuf:"varint,6,opt,name=final,proto3" json:"final,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *TopicMessage) Reset()         { *m = TopicMessage{} }
+func (m *TopicMessage) String() string { return proto.CompactTextString(m) }
+func (*TopicMessage) ProtoMessage()    {}
+func (*TopicMessage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chord_4c2dc8f28e7271ff, []int{22}
+}
+func (m *TopicMessage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TopicMessage.Unmarshal(m, b)
+}
+func (m *TopicMessage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TopicMessage.Marshal(b, m, deterministic)
+}
+func (dst *TopicMessage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TopicMessage.Merge(dst, src)
+}
+func (m *TopicMessage) XXX_Size() int {
+	return xxx_messageInfo_TopicMessage.Size(m)
+}
+func (m *TopicMessage) XXX_DiscardUnknown() {
+	xxx_messageInfo_TopicMessage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TopicMessage proto.InternalMessageInfo
+
+func (m *TopicMessage) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *TopicMessage) GetTopic() string {
+	if m != nil {
+		return m.Topic
+	}
+	return ""
+}
+
+func (m *TopicMessage) GetPayload() []byte {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func (m *TopicMessage) GetPublisher() *ContactInfo {
+	if m != nil {
+		return m.Publisher
+	}
+	return nil
+}
+
+func (m *TopicMessage) GetHops() uint32 {
+	if m != nil {
+		return m.Hops
+	}
+	return 0
+}
+
+func (m *TopicMessage) GetFinal() bool {
+	if m != nil {
+		return m.Final
+	}
+	return false
+}
+
+// AggregateRequest asks for the figures of the arc from the node up to
+// limit.
+type AggregateRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Limit                *NodeId  `protobuf:"bytes,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AggregateRequest) Reset()         { *m = AggregateRequest{} }
+func (m *AggregateRequest) String() string { return proto.CompactTextString(m) }
+func (*AggregateRequest) ProtoMessage()    {}
+func (*AggregateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chord_4c2dc8f28e7271ff, []int{23}
+}
+func (m *AggregateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AggregateRequest.Unmarshal(m, b)
+}
+func (m *AggregateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AggregateRequest.Marshal(b, m, deterministic)
+}
+func (dst *AggregateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AggregateRequest.Merge(dst, src)
+}
+func (m *AggregateRequest) XXX_Size() int {
+	return xxx_messageInfo_AggregateRequest.Size(m)
+}
+func (m *AggregateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AggregateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AggregateRequest proto.InternalMessageInfo
+
+func (m *AggregateRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *AggregateRequest) GetLimit() *NodeId {
+	if m != nil {
+		return m.Limit
+	}
+	return nil
+}
+
+type AggregateReply struct {
+	Nodes                uint64            `protobuf:"varint,1,opt,name=nodes,proto3" json:"nodes,omitempty"`
+	Keys                 uint64            `protobuf:"varint,2,opt,name=keys,proto3" json:"keys,omitempty"`
+	MinLoad              float64           `protobuf:"fixed64,3,opt,name=min_load,json=minLoad,proto3" json:"min_load,omitempty"`
+	MaxLoad              float64           `protobuf:"fixed64,4,opt,name=max_load,json=maxLoad,proto3" json:"max_load,omitempty"`
+	Versions             map[uint32]uint64 `protobuf:"bytes,5,rep,name=versions,proto3" json:"versions,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	Unreachable          []*ContactInfo    `protobuf:"bytes,6,rep,name=unreachable,proto3" json:"unreachable,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *AggregateReply) Reset()         { *m = AggregateReply{} }
+func (m *AggregateReply) String() string { return proto.CompactTextString(m) }
+func (*AggregateReply) ProtoMessage()    {}
+func (*AggregateReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chord_4c2dc8f28e7271ff, []int{24}
+}
+func (m *AggregateReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AggregateReply.Unmarshal(m, b)
+}
+func (m *AggregateReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AggregateReply.Marshal(b, m, deterministic)
+}
+func (dst *AggregateReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AggregateReply.Merge(dst, src)
+}
+func (m *AggregateReply) XXX_Size() int {
+	return xxx_messageInfo_AggregateReply.Size(m)
+}
+func (m *AggregateReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_AggregateReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AggregateReply proto.InternalMessageInfo
+
+func (m *AggregateReply) GetNodes() uint64 {
+	if m != nil {
+		return m.Nodes
+	}
+	return 0
+}
+
+func (m *AggregateReply) GetKeys() uint64 {
+	if m != nil {
+		return m.Keys
+	}
+	return 0
+}
+
+func (m *AggregateReply) GetMinLoad() float64 {
+	if m != nil {
+		return m.MinLoad
+	}
+	return 0
+}
+
+func (m *AggregateReply) GetMaxLoad() float64 {
+	if m != nil {
+		return m.MaxLoad
+	}
+	return 0
+}
+
+func (m *AggregateReply) GetVersions() map[uint32]uint64 {
+	if m != nil {
+		return m.Versions
+	}
+	return nil
+}
+
+func (m *AggregateReply) GetUnreachable() []*ContactInfo {
+	if m != nil {
+		return m.Unreachable
+	}
+	return nil
+}
+
+// NodeQuery asks for the nodes in the arc from the node up to limit whose
+// metadata has the given attributes.
+type NodeQuery struct {
+	Id                   string            `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Limit                *NodeId           `protobuf:"bytes,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Attributes           map[string]string `protobuf:"bytes,3,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *NodeQuery) Reset()         { *m = NodeQuery{} }
+func (m *NodeQuery) String() string { return proto.CompactTextString(m) }
+func (*NodeQuery) ProtoMessage()    {}
+func (*NodeQuery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chord_4c2dc8f28e7271ff, []int{25}
+}
+func (m *NodeQuery) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeQuery.Unmarshal(m, b)
+}
+func (m *NodeQuery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeQuery.Marshal(b, m, deterministic)
+}
+func (dst *NodeQuery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeQuery.Merge(dst, src)
+}
+func (m *NodeQuery) XXX_Size() int {
+	return xxx_messageInfo_NodeQuery.Size(m)
+}
+func (m *NodeQuery) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeQuery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeQuery proto.InternalMessageInfo
+
+func (m *NodeQuery) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *NodeQuery) GetLimit() *NodeId {
+	if m != nil {
+		return m.Limit
+	}
+	return nil
+}
+
+func (m *NodeQuery) GetAttributes() map[string]string {
+	if m != nil {
+		return m.Attributes
+	}
+	return nil
+}
+
+// ErrorInfo is attached to the status of a failed call, the status code
+// tells what kind of error it is.
+type ErrorInfo struct {
+	Argument             string       `protobuf:"bytes,1,opt,name=argument,proto3" json:"argument,omitempty"`
+	Node                 *ContactInfo `protobuf:"bytes,2,opt,name=node,proto3" json:"node,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *ErrorInfo) Reset()         { *m = ErrorInfo{} }
+func (m *ErrorInfo) String() string { return proto.CompactTextString(m) }
+func (*ErrorInfo) ProtoMessage()    {}
+func (*ErrorInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chord_4c2dc8f28e7271ff, []int{26}
+}
+func (m *ErrorInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ErrorInfo.Unmarshal(m, b)
+}
+func (m *ErrorInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ErrorInfo.Marshal(b, m, deterministic)
+}
+func (dst *ErrorInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ErrorInfo.Merge(dst, src)
+}
+func (m *ErrorInfo) XXX_Size() int {
+	return xxx_messageInfo_ErrorInfo.Size(m)
+}
+func (m *ErrorInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_ErrorInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ErrorInfo proto.InternalMessageInfo
+
+func (m *ErrorInfo) GetArgument() string {
+	if m != nil {
+		return m.Argument
+	}
+	return ""
+}
+
+func (m *ErrorInfo) GetNode() *ContactInfo {
+	if m != nil {
+		return m.Node
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Void)(nil), "chord.Void")
 	proto.RegisterType((*Id)(nil), "chord.Id")
 	proto.RegisterType((*NodeId)(nil), "chord.NodeId")
 	proto.RegisterType((*ContactInfo)(nil), "chord.ContactInfo")
+	proto.RegisterType((*ContactInfoList)(nil), "chord.ContactInfoList")
+	proto.RegisterType((*Key)(nil), "chord.Key")
+	proto.RegisterType((*Item)(nil), "chord.Item")
+	proto.RegisterType((*Hint)(nil), "chord.Hint")
+	proto.RegisterType((*LoadReport)(nil), "chord.LoadReport")
+	proto.RegisterType((*IdList)(nil), "chord.IdList")
+	proto.RegisterType((*Lookup)(nil), "chord.Lookup")
+	proto.RegisterType((*Heartbeat)(nil), "chord.Heartbeat")
+	proto.RegisterType((*HeartbeatList)(nil), "chord.HeartbeatList")
+	proto.RegisterType((*PredecessorReply)(nil), "chord.PredecessorReply")
+	proto.RegisterType((*FetchReply)(nil), "chord.FetchReply")
+	proto.RegisterType((*NeighbourUpdate)(nil), "chord.NeighbourUpdate")
+	proto.RegisterType((*NeighboursRequest)(nil), "chord.NeighboursRequest")
+	proto.RegisterType((*Message)(nil), "chord.Message")
+	proto.RegisterType((*Reply)(nil), "chord.Reply")
+	proto.RegisterType((*BroadcastMessage)(nil), "chord.BroadcastMessage")
+	proto.RegisterType((*BroadcastAck)(nil), "chord.BroadcastAck")
+	proto.RegisterType((*TopicJoin)(nil), "chord.TopicJoin")
+	proto.RegisterType((*TopicMessage)(nil), "chord.TopicMessage")
+	proto.RegisterType((*AggregateRequest)(nil), "chord.AggregateRequest")
+	proto.RegisterType((*AggregateReply)(nil), "chord.AggregateReply")
+	proto.RegisterMapType((map[uint32]uint64)(nil), "chord.AggregateReply.VersionsEntry")
+	proto.RegisterType((*NodeQuery)(nil), "chord.NodeQuery")
+	proto.RegisterMapType((map[string]string)(nil), "chord.NodeQuery.AttributesEntry")
+	proto.RegisterType((*ErrorInfo)(nil), "chord.ErrorInfo")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// ChordClient is the client API for Chord service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ChordClient interface {
+	Ping(ctx context.Context, in *Void, opts ...grpc.CallOption) (*ContactInfo, error)
+	FindSuccessor(ctx context.Context, in *Id, opts ...grpc.CallOption) (*ContactInfo, error)
+	ClosestPrecedingNode(ctx context.Context, in *Id, opts ...grpc.CallOption) (*ContactInfo, error)
+	Predecessor(ctx context.Context, in *Void, opts ...grpc.CallOption) (*ContactInfo, error)
+	Successor(ctx context.Context, in *Void, opts ...grpc.CallOption) (*ContactInfo, error)
+	Notify(ctx context.Context, in *ContactInfo, opts ...grpc.CallOption) (*Void, error)
+	SuccessorList(ctx context.Context, in *Void, opts ...grpc.CallOption) (*ContactInfoList, error)
+	Store(ctx context.Context, in *Item, opts ...grpc.CallOption) (*Void, error)
+	Fetch(ctx context.Context, in *Key, opts ...grpc.CallOption) (*FetchReply, error)
+	StoreHint(ctx context.Context, in *Hint, opts ...grpc.CallOption) (*Void, error)
+	Load(ctx context.Context, in *Void, opts ...grpc.CallOption) (*LoadReport, error)
+	FindSuccessors(ctx context.Context, in *IdList, opts ...grpc.CallOption) (Chord_FindSuccessorsClient, error)
+	ProbeNode(ctx context.Context, in *ContactInfo, opts ...grpc.CallOption) (*Void, error)
+	Gossip(ctx context.Context, in *HeartbeatList, opts ...grpc.CallOption) (*HeartbeatList, error)
+	CurrentPredecessor(ctx context.Context, in *Void, opts ...grpc.CallOption) (*PredecessorReply, error)
+	Watch(ctx context.Context, in *Void, opts ...grpc.CallOption) (Chord_WatchClient, error)
+	Neighbours(ctx context.Context, in *NeighboursRequest, opts ...grpc.CallOption) (*NeighbourUpdate, error)
+	Route(ctx context.Context, in *Message, opts ...grpc.CallOption) (*Reply, error)
+	Broadcast(ctx context.Context, in *BroadcastMessage, opts ...grpc.CallOption) (*BroadcastAck, error)
+	JoinTopic(ctx context.Context, in *TopicJoin, opts ...grpc.CallOption) (*Void, error)
+	LeaveTopic(ctx context.Context, in *TopicJoin, opts ...grpc.CallOption) (*Void, error)
+	Publish(ctx context.Context, in *TopicMessage, opts ...grpc.CallOption) (*Void, error)
+	Multicast(ctx context.Context, in *TopicMessage, opts ...grpc.CallOption) (*Void, error)
+	Aggregate(ctx context.Context, in *AggregateRequest, opts ...grpc.CallOption) (*AggregateReply, error)
+	FindNodes(ctx context.Context, in *NodeQuery, opts ...grpc.CallOption) (*ContactInfoList, error)
+}
+
+type chordClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewChordClient(cc *grpc.ClientConn) ChordClient {
+	return &chordClient{cc}
+}
+
+func (c *chordClient) Ping(ctx context.Context, in *Void, opts ...grpc.CallOption) (*ContactInfo, error) {
+	out := new(ContactInfo)
+	err := c.cc.Invoke(ctx, "/chord.Chord/Ping", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chordClient) FindSuccessor(ctx context.Context, in *Id, opts ...grpc.CallOption) (*ContactInfo, error) {
+	out := new(ContactInfo)
+	err := c.cc.Invoke(ctx, "/chord.Chord/FindSuccessor", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chordClient) ClosestPrecedingNode(ctx context.Context, in *Id, opts ...grpc.CallOption) (*ContactInfo, error) {
+	out := new(ContactInfo)
+	err := c.cc.Invoke(ctx, "/chord.Chord/ClosestPrecedingNode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chordClient) Predecessor(ctx context.Context, in *Void, opts ...grpc.CallOption) (*ContactInfo, error) {
+	out := new(ContactInfo)
+	err := c.cc.Invoke(ctx, "/chord.Chord/Predecessor", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chordClient) Successor(ctx context.Context, in *Void, opts ...grpc.CallOption) (*ContactInfo, error) {
+	out := new(ContactInfo)
+	err := c.cc.Invoke(ctx, "/chord.Chord/Successor", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chordClient) Notify(ctx context.Context, in *ContactInfo, opts ...grpc.CallOption) (*Void, error) {
+	out := new(Void)
+	err := c.cc.Invoke(ctx, "/chord.Chord/Notify", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chordClient) SuccessorList(ctx context.Context, in *Void, opts ...grpc.CallOption) (*ContactInfoList, error) {
+	out := new(ContactInfoList)
+	err := c.cc.Invoke(ctx, "/chord.Chord/SuccessorList", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chordClient) Store(ctx context.Context, in *Item, opts ...grpc.CallOption) (*Void, error) {
+	out := new(Void)
+	err := c.cc.Invoke(ctx, "/chord.Chord/Store", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+func (c *chordClient) Fetch(ctx context.Context, in *Key, opts ...grpc.CallOption) (*FetchReply, error) {
+	out := new(FetchReply)
+	err := c.cc.Invoke(ctx, "/chord.Chord/Fetch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+func (c *chordClient) StoreHint(ctx context.Context, in *Hint, opts ...grpc.CallOption) (*Void, error) {
+	out := new(Void)
+	err := c.cc.Invoke(ctx, "/chord.Chord/StoreHint", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chordClient) Load(ctx context.Context, in *Void, opts ...grpc.CallOption) (*LoadReport, error) {
+	out := new(LoadReport)
+	err := c.cc.Invoke(ctx, "/chord.Chord/Load", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chordClient) FindSuccessors(ctx context.Context, in *IdList, opts ...grpc.CallOption) (Chord_FindSuccessorsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Chord_serviceDesc.Streams[0], "/chord.Chord/FindSuccessors", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &chordFindSuccessorsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Chord_FindSuccessorsClient interface {
+	Recv() (*Lookup, error)
+	grpc.ClientStream
+}
+
+type chordFindSuccessorsClient struct {
+	grpc.ClientStream
+}
+
+func (x *chordFindSuccessorsClient) Recv() (*Lookup, error) {
+	m := new(Lookup)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *chordClient) ProbeNode(ctx context.Context, in *ContactInfo, opts ...grpc.CallOption) (*Void, error) {
+	out := new(Void)
+	err := c.cc.Invoke(ctx, "/chord.Chord/ProbeNode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chordClient) Gossip(ctx context.Context, in *HeartbeatList, opts ...grpc.CallOption) (*HeartbeatList, error) {
+	out := new(HeartbeatList)
+	err := c.cc.Invoke(ctx, "/chord.Chord/Gossip", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chordClient) CurrentPredecessor(ctx context.Context, in *Void, opts ...grpc.CallOption) (*PredecessorReply, error) {
+	out := new(PredecessorReply)
+	err := c.cc.Invoke(ctx, "/chord.Chord/CurrentPredecessor", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chordClient) Watch(ctx context.Context, in *Void, opts ...grpc.CallOption) (Chord_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Chord_serviceDesc.Streams[1], "/chord.Chord/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &chordWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Chord_WatchClient interface {
+	Recv() (*NeighbourUpdate, error)
+	grpc.ClientStream
+}
+
+type chordWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *chordWatchClient) Recv() (*NeighbourUpdate, error) {
+	m := new(NeighbourUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *chordClient) Neighbours(ctx context.Context, in *NeighboursRequest, opts ...grpc.CallOption) (*NeighbourUpdate, error) {
+	out := new(NeighbourUpdate)
+	err := c.cc.Invoke(ctx, "/chord.Chord/Neighbours", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chordClient) Route(ctx context.Context, in *Message, opts ...grpc.CallOption) (*Reply, error) {
+	out := new(Reply)
+	err := c.cc.Invoke(ctx, "/chord.Chord/Route", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chordClient) Broadcast(ctx context.Context, in *BroadcastMessage, opts ...grpc.CallOption) (*BroadcastAck, error) {
+	out := new(BroadcastAck)
+	err := c.cc.Invoke(ctx, "/chord.Chord/Broadcast", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chordClient) JoinTopic(ctx context.Context, in *TopicJoin, opts ...grpc.CallOption) (*Void, error) {
+	out := new(Void)
+	err := c.cc.Invoke(ctx, "/chord.Chord/JoinTopic", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chordClient) LeaveTopic(ctx context.Context, in *TopicJoin, opts ...grpc.CallOption) (*Void, error) {
+	out := new(Void)
+	err := c.cc.Invoke(ctx, "/chord.Chord/LeaveTopic", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chordClient) Publish(ctx context.Context, in *TopicMessage, opts ...grpc.CallOption) (*Void, error) {
+	out := new(Void)
+	err := c.cc.Invoke(ctx, "/chord.Chord/Publish", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chordClient) Multicast(ctx context.Context, in *TopicMessage, opts ...grpc.CallOption) (*Void, error) {
+	out := new(Void)
+	err := c.cc.Invoke(ctx, "/chord.Chord/Multicast", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chordClient) Aggregate(ctx context.Context, in *AggregateRequest, opts ...grpc.CallOption) (*AggregateReply, error) {
+	out := new(AggregateReply)
+	err := c.cc.Invoke(ctx, "/chord.Chord/Aggregate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chordClient) FindNodes(ctx context.Context, in *NodeQuery, opts ...grpc.CallOption) (*ContactInfoList, error) {
+	out := new(ContactInfoList)
+	err := c.cc.Invoke(ctx, "/chord.Chord/FindNodes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ChordServer is the server API for Chord service.
+type ChordServer interface {
+	Ping(context.Context, *Void) (*ContactInfo, error)
+	FindSuccessor(context.Context, *Id) (*ContactInfo, error)
+	ClosestPrecedingNode(context.Context, *Id) (*ContactInfo, error)
+	Predecessor(context.Context, *Void) (*ContactInfo, error)
+	Successor(context.Context, *Void) (*ContactInfo, error)
+	Notify(context.Context, *ContactInfo) (*Void, error)
+	SuccessorList(context.Context, *Void) (*ContactInfoList, error)
+	Store(context.Context, *Item) (*Void, error)
+	Fetch(context.Context, *Key) (*FetchReply, error)
+	StoreHint(context.Context, *Hint) (*Void, error)
+	Load(context.Context, *Void) (*LoadReport, error)
+	FindSuccessors(*IdList, Chord_FindSuccessorsServer) error
+	ProbeNode(context.Context, *ContactInfo) (*Void, error)
+	Gossip(context.Context, *HeartbeatList) (*HeartbeatList, error)
+	CurrentPredecessor(context.Context, *Void) (*PredecessorReply, error)
+	Watch(*Void, Chord_WatchServer) error
+	Neighbours(context.Context, *NeighboursRequest) (*NeighbourUpdate, error)
+	Route(context.Context, *Message) (*Reply, error)
+	Broadcast(context.Context, *BroadcastMessage) (*BroadcastAck, error)
+	JoinTopic(context.Context, *TopicJoin) (*Void, error)
+	LeaveTopic(context.Context, *TopicJoin) (*Void, error)
+	Publish(context.Context, *TopicMessage) (*Void, error)
+	Multicast(context.Context, *TopicMessage) (*Void, error)
+	Aggregate(context.Context, *AggregateRequest) (*AggregateReply, error)
+	FindNodes(context.Context, *NodeQuery) (*ContactInfoList, error)
+}
+
+func RegisterChordServer(s *grpc.Server, srv ChordServer) {
+	s.RegisterService(&_Chord_serviceDesc, srv)
+}
+
+func _Chord_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Void)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChordServer).Ping(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/chord.Chord/Ping",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChordServer).Ping(ctx, req.(*Void))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Chord_FindSuccessor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Id)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChordServer).FindSuccessor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/chord.Chord/FindSuccessor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChordServer).FindSuccessor(ctx, req.(*Id))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Chord_ClosestPrecedingNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Id)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChordServer).ClosestPrecedingNode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/chord.Chord/ClosestPrecedingNode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChordServer).ClosestPrecedingNode(ctx, req.(*Id))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Chord_Predecessor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Void)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChordServer).Predecessor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/chord.Chord/Predecessor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChordServer).Predecessor(ctx, req.(*Void))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Chord_Successor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Void)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChordServer).Successor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/chord.Chord/Successor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChordServer).Successor(ctx, req.(*Void))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Chord_Notify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ContactInfo)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChordServer).Notify(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/chord.Chord/Notify",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChordServer).Notify(ctx, req.(*ContactInfo))
+	}
+	return interceptor(ctx, in, info, handler)
+}
 
-// ChordClient is the client API for Chord service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type ChordClient interface {
-	Ping(ctx context.Context, in *Void, opts ...grpc.CallOption) (*ContactInfo, error)
-	FindSuccessor(ctx context.Context, in *Id, opts ...grpc.CallOption) (*ContactInfo, error)
-	ClosestPrecedingNode(ctx context.Context, in *Id, opts ...grpc.CallOption) (*ContactInfo, error)
-	Predecessor(ctx context.Context, in *Void, opts ...grpc.CallOption) (*ContactInfo, error)
-	Successor(ctx context.Context, in *Void, opts ...grpc.CallOption) (*ContactInfo, error)
-	Notify(ctx context.Context, in *ContactInfo, opts ...grpc.CallOption) (*Void, error)
+func _Chord_SuccessorList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Void)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChordServer).SuccessorList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/chord.Chord/SuccessorList",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChordServer).SuccessorList(ctx, req.(*Void))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type chordClient struct {
-	cc *grpc.ClientConn
+func _Chord_Store_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Item)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChordServer).Store(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/chord.Chord/Store",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChordServer).Store(ctx, req.(*Item))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func NewChordClient(cc *grpc.ClientConn) ChordClient {
-	return &chordClient{cc}
+func _Chord_Fetch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Key)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChordServer).Fetch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/chord.Chord/Fetch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChordServer).Fetch(ctx, req.(*Key))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *chordClient) Ping(ctx context.Context, in *Void, opts ...grpc.CallOption) (*ContactInfo, error) {
-	out := new(ContactInfo)
-	err := c.cc.Invoke(ctx, "/chord.Chord/Ping", in, out, opts...)
-	if err != nil {
+func _Chord_StoreHint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Hint)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(ChordServer).StoreHint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/chord.Chord/StoreHint",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChordServer).StoreHint(ctx, req.(*Hint))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *chordClient) FindSuccessor(ctx context.Context, in *Id, opts ...grpc.CallOption) (*ContactInfo, error) {
-	out := new(ContactInfo)
-	err := c.cc.Invoke(ctx, "/chord.Chord/FindSuccessor", in, out, opts...)
-	if err != nil {
+func _Chord_Load_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Void)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(ChordServer).Load(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/chord.Chord/Load",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChordServer).Load(ctx, req.(*Void))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *chordClient) ClosestPrecedingNode(ctx context.Context, in *Id, opts ...grpc.CallOption) (*ContactInfo, error) {
-	out := new(ContactInfo)
-	err := c.cc.Invoke(ctx, "/chord.Chord/ClosestPrecedingNode", in, out, opts...)
-	if err != nil {
+func _Chord_FindSuccessors_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(IdList)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ChordServer).FindSuccessors(m, &chordFindSuccessorsServer{stream})
+}
+
+type Chord_FindSuccessorsServer interface {
+	Send(*Lookup) error
+	grpc.ServerStream
+}
+
+type chordFindSuccessorsServer struct {
+	grpc.ServerStream
+}
+
+func (x *chordFindSuccessorsServer) Send(m *Lookup) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Chord_ProbeNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ContactInfo)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(ChordServer).ProbeNode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/chord.Chord/ProbeNode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChordServer).ProbeNode(ctx, req.(*ContactInfo))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *chordClient) Predecessor(ctx context.Context, in *Void, opts ...grpc.CallOption) (*ContactInfo, error) {
-	out := new(ContactInfo)
-	err := c.cc.Invoke(ctx, "/chord.Chord/Predecessor", in, out, opts...)
-	if err != nil {
+func _Chord_Gossip_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HeartbeatList)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(ChordServer).Gossip(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/chord.Chord/Gossip",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChordServer).Gossip(ctx, req.(*HeartbeatList))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *chordClient) Successor(ctx context.Context, in *Void, opts ...grpc.CallOption) (*ContactInfo, error) {
-	out := new(ContactInfo)
-	err := c.cc.Invoke(ctx, "/chord.Chord/Successor", in, out, opts...)
-	if err != nil {
+func _Chord_CurrentPredecessor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Void)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(ChordServer).CurrentPredecessor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/chord.Chord/CurrentPredecessor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChordServer).CurrentPredecessor(ctx, req.(*Void))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *chordClient) Notify(ctx context.Context, in *ContactInfo, opts ...grpc.CallOption) (*Void, error) {
-	out := new(Void)
-	err := c.cc.Invoke(ctx, "/chord.Chord/Notify", in, out, opts...)
-	if err != nil {
+func _Chord_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Void)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ChordServer).Watch(m, &chordWatchServer{stream})
+}
+
+type Chord_WatchServer interface {
+	Send(*NeighbourUpdate) error
+	grpc.ServerStream
+}
+
+type chordWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *chordWatchServer) Send(m *NeighbourUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Chord_Neighbours_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NeighboursRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(ChordServer).Neighbours(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/chord.Chord/Neighbours",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChordServer).Neighbours(ctx, req.(*NeighboursRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// ChordServer is the server API for Chord service.
-type ChordServer interface {
-	Ping(context.Context, *Void) (*ContactInfo, error)
-	FindSuccessor(context.Context, *Id) (*ContactInfo, error)
-	ClosestPrecedingNode(context.Context, *Id) (*ContactInfo, error)
-	Predecessor(context.Context, *Void) (*ContactInfo, error)
-	Successor(context.Context, *Void) (*ContactInfo, error)
-	Notify(context.Context, *ContactInfo) (*Void, error)
+func _Chord_Route_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Message)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChordServer).Route(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/chord.Chord/Route",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChordServer).Route(ctx, req.(*Message))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterChordServer(s *grpc.Server, srv ChordServer) {
-	s.RegisterService(&_Chord_serviceDesc, srv)
+func _Chord_Broadcast_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BroadcastMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChordServer).Broadcast(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/chord.Chord/Broadcast",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChordServer).Broadcast(ctx, req.(*BroadcastMessage))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _Chord_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Void)
+func _Chord_JoinTopic_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TopicJoin)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ChordServer).Ping(ctx, in)
+		return srv.(ChordServer).JoinTopic(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/chord.Chord/Ping",
+		FullMethod: "/chord.Chord/JoinTopic",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ChordServer).Ping(ctx, req.(*Void))
+		return srv.(ChordServer).JoinTopic(ctx, req.(*TopicJoin))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Chord_FindSuccessor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Id)
+func _Chord_LeaveTopic_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TopicJoin)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ChordServer).FindSuccessor(ctx, in)
+		return srv.(ChordServer).LeaveTopic(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/chord.Chord/FindSuccessor",
+		FullMethod: "/chord.Chord/LeaveTopic",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ChordServer).FindSuccessor(ctx, req.(*Id))
+		return srv.(ChordServer).LeaveTopic(ctx, req.(*TopicJoin))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Chord_ClosestPrecedingNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Id)
+func _Chord_Publish_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TopicMessage)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ChordServer).ClosestPrecedingNode(ctx, in)
+		return srv.(ChordServer).Publish(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/chord.Chord/ClosestPrecedingNode",
+		FullMethod: "/chord.Chord/Publish",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ChordServer).ClosestPrecedingNode(ctx, req.(*Id))
+		return srv.(ChordServer).Publish(ctx, req.(*TopicMessage))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Chord_Predecessor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Void)
+func _Chord_Multicast_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TopicMessage)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ChordServer).Predecessor(ctx, in)
+		return srv.(ChordServer).Multicast(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/chord.Chord/Predecessor",
+		FullMethod: "/chord.Chord/Multicast",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ChordServer).Predecessor(ctx, req.(*Void))
+		return srv.(ChordServer).Multicast(ctx, req.(*TopicMessage))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Chord_Successor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Void)
+func _Chord_Aggregate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AggregateRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ChordServer).Successor(ctx, in)
+		return srv.(ChordServer).Aggregate(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/chord.Chord/Successor",
+		FullMethod: "/chord.Chord/Aggregate",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ChordServer).Successor(ctx, req.(*Void))
+		return srv.(ChordServer).Aggregate(ctx, req.(*AggregateRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Chord_Notify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ContactInfo)
+func _Chord_FindNodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeQuery)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ChordServer).Notify(ctx, in)
+		return srv.(ChordServer).FindNodes(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/chord.Chord/Notify",
+		FullMethod: "/chord.Chord/FindNodes",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ChordServer).Notify(ctx, req.(*ContactInfo))
+		return srv.(ChordServer).FindNodes(ctx, req.(*NodeQuery))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -430,31 +2291,180 @@ var _Chord_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Notify",
 			Handler:    _Chord_Notify_Handler,
 		},
+		{
+			MethodName: "SuccessorList",
+			Handler:    _Chord_SuccessorList_Handler,
+		},
+		{
+			MethodName: "Store",
+			Handler:    _Chord_Store_Handler,
+		},
+		{
+			MethodName: "Fetch",
+			Handler:    _Chord_Fetch_Handler,
+		},
+		{
+			MethodName: "StoreHint",
+			Handler:    _Chord_StoreHint_Handler,
+		},
+		{
+			MethodName: "Load",
+			Handler:    _Chord_Load_Handler,
+		},
+		{
+			MethodName: "ProbeNode",
+			Handler:    _Chord_ProbeNode_Handler,
+		},
+		{
+			MethodName: "Gossip",
+			Handler:    _Chord_Gossip_Handler,
+		},
+		{
+			MethodName: "CurrentPredecessor",
+			Handler:    _Chord_CurrentPredecessor_Handler,
+		},
+		{
+			MethodName: "Neighbours",
+			Handler:    _Chord_Neighbours_Handler,
+		},
+		{
+			MethodName: "Route",
+			Handler:    _Chord_Route_Handler,
+		},
+		{
+			MethodName: "Broadcast",
+			Handler:    _Chord_Broadcast_Handler,
+		},
+		{
+			MethodName: "JoinTopic",
+			Handler:    _Chord_JoinTopic_Handler,
+		},
+		{
+			MethodName: "LeaveTopic",
+			Handler:    _Chord_LeaveTopic_Handler,
+		},
+		{
+			MethodName: "Publish",
+			Handler:    _Chord_Publish_Handler,
+		},
+		{
+			MethodName: "Multicast",
+			Handler:    _Chord_Multicast_Handler,
+		},
+		{
+			MethodName: "Aggregate",
+			Handler:    _Chord_Aggregate_Handler,
+		},
+		{
+			MethodName: "FindNodes",
+			Handler:    _Chord_FindNodes_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "FindSuccessors",
+			Handler:       _Chord_FindSuccessors_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Watch",
+			Handler:       _Chord_Watch_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "chord.proto",
 }
 
-func init() { proto.RegisterFile("chord.proto", fileDescriptor_chord_60da2256c3abf9d6) }
-
-var fileDescriptor_chord_60da2256c3abf9d6 = []byte{
-	// 282 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x51, 0x4f, 0x4b, 0xc3, 0x30,
-	0x14, 0xdf, 0xfa, 0x6f, 0xf4, 0xd5, 0x89, 0x3c, 0x3c, 0x94, 0x82, 0x20, 0x39, 0x4d, 0x84, 0x32,
-	0xe6, 0xc1, 0xbb, 0x05, 0xa1, 0x17, 0x29, 0x13, 0x3c, 0x78, 0x32, 0xf6, 0x65, 0x6b, 0xa0, 0x34,
-	0x25, 0xa9, 0xc2, 0xbe, 0x93, 0x1f, 0x52, 0x92, 0x75, 0xb8, 0x83, 0xe2, 0x6e, 0xef, 0x97, 0xfc,
-	0xfe, 0x25, 0x0f, 0x92, 0xba, 0x51, 0x9a, 0xf2, 0x5e, 0xab, 0x41, 0x61, 0xe8, 0x00, 0x8b, 0x20,
-	0x78, 0x51, 0x92, 0xd8, 0x02, 0xbc, 0x92, 0xf0, 0x1c, 0x3c, 0x49, 0xe9, 0xf4, 0x7a, 0xba, 0x88,
-	0xd7, 0x9e, 0x24, 0x44, 0x08, 0x1a, 0x6e, 0x9a, 0xd4, 0x73, 0x27, 0x6e, 0x66, 0x19, 0x44, 0x4f,
-	0x8a, 0x44, 0x49, 0x78, 0x01, 0xfe, 0x27, 0x6f, 0x1d, 0xfd, 0x6c, 0x6d, 0x47, 0xf6, 0x06, 0x49,
-	0xa1, 0xba, 0x81, 0xd7, 0x43, 0xd9, 0x6d, 0x14, 0xa6, 0x30, 0xe3, 0x44, 0x5a, 0x18, 0x33, 0x7a,
-	0x1e, 0x20, 0x5e, 0xb9, 0x20, 0x6b, 0x9b, 0xac, 0xe6, 0xf9, 0xbe, 0xd7, 0xde, 0xd5, 0xe5, 0xa6,
-	0x30, 0xeb, 0xf9, 0xae, 0x55, 0x9c, 0x52, 0xdf, 0xb9, 0x1f, 0xe0, 0xea, 0xcb, 0x83, 0xb0, 0xb0,
-	0x74, 0xbc, 0x81, 0xa0, 0x92, 0xdd, 0x16, 0x93, 0x51, 0x6e, 0x9f, 0x91, 0xe1, 0x08, 0x8e, 0x5a,
-	0xb0, 0x09, 0x2e, 0x61, 0xfe, 0x28, 0x3b, 0x7a, 0xfe, 0xa8, 0x6b, 0x61, 0x8c, 0xd2, 0x18, 0x8f,
-	0xb4, 0xf2, 0x2f, 0xc5, 0x3d, 0x5c, 0x16, 0xad, 0x32, 0xc2, 0x0c, 0x95, 0x16, 0xb5, 0x20, 0xd9,
-	0x6d, 0x6d, 0xbd, 0xff, 0x85, 0x4b, 0x48, 0x2a, 0x2d, 0x48, 0x8c, 0x41, 0x27, 0x94, 0xcb, 0x21,
-	0xfe, 0x29, 0x76, 0x02, 0xff, 0xd6, 0xfe, 0xff, 0x20, 0x37, 0x3b, 0xfc, 0xe5, 0x3e, 0x3b, 0x36,
-	0x60, 0x93, 0x87, 0xf0, 0xd5, 0xe7, 0xbd, 0x7c, 0x8f, 0xdc, 0xce, 0xef, 0xbe, 0x03, 0x00, 0x00,
-	0xff, 0xff, 0xf2, 0xd4, 0xad, 0x7d, 0x02, 0x02, 0x00, 0x00,
+func init() { proto.RegisterFile("chord.proto", fileDescriptor_chord_4c2dc8f28e7271ff) }
+
+var fileDescriptor_chord_4c2dc8f28e7271ff = []byte{
+	// 1401 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x57, 0x6f, 0x6f, 0xdb, 0x36,
+	0x13, 0xb7, 0x6c, 0xc9, 0x89, 0xce, 0x76, 0xe2, 0xb2, 0x7d, 0x9e, 0xfa, 0x31, 0x9e, 0xa1, 0x19,
+	0x0b, 0xb4, 0x6e, 0xb7, 0x65, 0x41, 0x36, 0x6c, 0xc3, 0xba, 0xa2, 0x4d, 0x83, 0xfe, 0xc9, 0x9a,
+	0x76, 0x99, 0xba, 0x75, 0xc0, 0x80, 0xa1, 0xa0, 0x25, 0xc6, 0x26, 0xa2, 0x88, 0x2a, 0x45, 0x05,
+	0xf5, 0x57, 0xd9, 0x8b, 0xed, 0xcd, 0x5e, 0xed, 0x5b, 0xec, 0xfd, 0xbe, 0xcb, 0xbe, 0xc2, 0x40,
+	0x8a, 0x92, 0x65, 0xc5, 0x4a, 0x8c, 0x61, 0xef, 0x78, 0xbc, 0x1f, 0x8f, 0x77, 0xbf, 0x3b, 0xf1,
+	0x4e, 0xd0, 0xf1, 0xa7, 0x5c, 0x04, 0xdb, 0xb1, 0xe0, 0x92, 0x23, 0x47, 0x0b, 0xb8, 0x0d, 0xf6,
+	0x6b, 0xce, 0x02, 0x3c, 0x82, 0xe6, 0x41, 0x80, 0x36, 0xa0, 0xc9, 0x82, 0x81, 0xb5, 0x65, 0x8d,
+	0x5c, 0xaf, 0xc9, 0x02, 0x84, 0xc0, 0x9e, 0x92, 0x64, 0x3a, 0x68, 0xea, 0x1d, 0xbd, 0xc6, 0x43,
+	0x68, 0xbf, 0xe4, 0x01, 0x3d, 0x08, 0x50, 0x1f, 0x5a, 0x67, 0x24, 0xd4, 0xf0, 0xae, 0xa7, 0x96,
+	0xf8, 0x17, 0x0b, 0x3a, 0xfb, 0x3c, 0x92, 0xc4, 0x97, 0x07, 0xd1, 0x31, 0x47, 0x03, 0x58, 0x23,
+	0x41, 0x20, 0x68, 0x92, 0x18, 0xa3, 0xb9, 0x88, 0xde, 0xd3, 0x37, 0x29, 0xbb, 0x9d, 0xdd, 0xde,
+	0x76, 0xe6, 0x58, 0x66, 0x56, 0x5f, 0x3c, 0x80, 0xb5, 0x98, 0xcc, 0x42, 0x4e, 0x82, 0x41, 0x4b,
+	0x9b, 0xcf, 0x45, 0xa5, 0x39, 0xa3, 0x22, 0x61, 0x3c, 0x1a, 0xd8, 0x5b, 0xd6, 0xa8, 0xe7, 0xe5,
+	0x22, 0xc2, 0xd0, 0xf5, 0x49, 0x4c, 0xc6, 0x2c, 0x64, 0x92, 0xd1, 0x64, 0xe0, 0x6c, 0x59, 0x23,
+	0xdb, 0x5b, 0xd8, 0xc3, 0x7b, 0xb0, 0x59, 0xf2, 0xef, 0x90, 0x25, 0x12, 0x6d, 0xc3, 0xba, 0x9f,
+	0x6d, 0x29, 0x27, 0x5b, 0xa3, 0xce, 0x2e, 0x32, 0xfe, 0x94, 0x90, 0x5e, 0x81, 0xc1, 0xd7, 0xa1,
+	0xf5, 0x9c, 0xce, 0x54, 0xf0, 0x27, 0x74, 0x66, 0xc2, 0x52, 0x4b, 0xfc, 0x0c, 0xec, 0x03, 0x49,
+	0x4f, 0xcf, 0x6b, 0xd0, 0x35, 0x70, 0xce, 0x48, 0x98, 0x52, 0x1d, 0x6f, 0xd7, 0xcb, 0x84, 0x72,
+	0x24, 0x2d, 0xed, 0x6a, 0x2e, 0xe2, 0x57, 0x60, 0x3f, 0x63, 0x91, 0x44, 0x77, 0xa1, 0x2d, 0x89,
+	0x98, 0x50, 0xa9, 0x8d, 0x2d, 0x77, 0xcc, 0x20, 0xd0, 0x0d, 0xb0, 0x99, 0xa4, 0xa7, 0x86, 0xd2,
+	0x8e, 0x41, 0x2a, 0x87, 0x3c, 0xad, 0xc0, 0x12, 0xe0, 0x90, 0x93, 0xc0, 0xa3, 0x31, 0x17, 0xd2,
+	0xf0, 0x6f, 0xd5, 0xf1, 0xdf, 0x87, 0x16, 0x11, 0xbe, 0x36, 0x66, 0x79, 0x6a, 0x89, 0xde, 0x87,
+	0xae, 0xa0, 0x6f, 0x53, 0x9a, 0xc8, 0x37, 0x82, 0x48, 0xaa, 0x5d, 0xb6, 0xbc, 0x8e, 0xd9, 0xf3,
+	0x88, 0xa4, 0xaa, 0x5a, 0x4e, 0xe8, 0x2c, 0xd1, 0x79, 0xb1, 0x3d, 0xbd, 0xc6, 0x77, 0xa0, 0x7d,
+	0x10, 0x68, 0x9e, 0x6f, 0x40, 0x8b, 0x05, 0x39, 0xc5, 0x95, 0x2b, 0x95, 0x06, 0x73, 0x68, 0x1f,
+	0x72, 0x7e, 0x92, 0xc6, 0x97, 0x39, 0xb7, 0x03, 0x6e, 0x92, 0xfa, 0x3e, 0x4d, 0x12, 0x2e, 0x06,
+	0xcd, 0x5a, 0x66, 0xe6, 0x20, 0x95, 0x80, 0x63, 0x9e, 0x46, 0x59, 0x31, 0xad, 0x7b, 0x99, 0x80,
+	0x0f, 0xc0, 0x7d, 0x46, 0x89, 0x90, 0x63, 0x4a, 0x24, 0xba, 0x05, 0x36, 0x8b, 0x8e, 0xf9, 0x05,
+	0x4c, 0x6b, 0xbd, 0x32, 0xe5, 0xf3, 0x34, 0x92, 0xfa, 0x62, 0xdb, 0xcb, 0x04, 0xbc, 0x07, 0xbd,
+	0xc2, 0x94, 0x8e, 0x76, 0x07, 0x60, 0x9a, 0x6f, 0xe4, 0x41, 0xf7, 0x8d, 0xd1, 0x02, 0xe9, 0x95,
+	0x30, 0xf8, 0x2d, 0xf4, 0x8f, 0x04, 0x0d, 0x68, 0xe6, 0xb2, 0x47, 0xe3, 0x70, 0x86, 0x6e, 0xc3,
+	0xe6, 0x94, 0x24, 0x6f, 0xe2, 0xf9, 0xbe, 0xf6, 0x6f, 0xdd, 0xdb, 0x98, 0x92, 0xa4, 0x84, 0x46,
+	0x9f, 0x42, 0xa7, 0x0c, 0xaa, 0x27, 0xa5, 0x0c, 0xc3, 0xfb, 0x00, 0x4f, 0xa8, 0xf4, 0xa7, 0xd9,
+	0x65, 0x05, 0x49, 0x56, 0x89, 0xa4, 0xcb, 0xeb, 0xea, 0x4f, 0x0b, 0x36, 0x5f, 0x52, 0x36, 0x99,
+	0x8e, 0x79, 0x2a, 0xbe, 0x8f, 0x03, 0x55, 0x09, 0xb7, 0xc0, 0x8e, 0x78, 0x40, 0x2f, 0x22, 0x53,
+	0xe9, 0x97, 0xc5, 0xd7, 0x5c, 0x25, 0xbe, 0xd6, 0x4a, 0xf1, 0xa1, 0x5d, 0x80, 0xa2, 0x06, 0x54,
+	0x59, 0xd6, 0x7d, 0xdc, 0x25, 0x14, 0x7e, 0x00, 0x57, 0x8a, 0x68, 0x12, 0x2f, 0xab, 0x6e, 0xf5,
+	0x21, 0x46, 0x5c, 0xb2, 0xe3, 0xd9, 0x45, 0x1f, 0x62, 0x86, 0xc0, 0x3f, 0x5b, 0xb0, 0xf6, 0x82,
+	0x26, 0x09, 0x99, 0x50, 0x74, 0x63, 0xfe, 0x14, 0x9c, 0xaf, 0x79, 0xf5, 0x32, 0x94, 0xde, 0xb9,
+	0xe6, 0xe2, 0x3b, 0x77, 0x17, 0xda, 0x5c, 0xb0, 0x09, 0x8b, 0x2e, 0x08, 0xd6, 0x20, 0xf4, 0x33,
+	0xcd, 0xe3, 0xc4, 0x3c, 0x88, 0x7a, 0xad, 0xb3, 0xc9, 0x22, 0x12, 0x0e, 0x1c, 0x93, 0x4d, 0x25,
+	0xe0, 0x9f, 0xc0, 0xc9, 0x92, 0x5d, 0xba, 0xd8, 0x5a, 0xbc, 0x38, 0xcf, 0x5d, 0xf3, 0x92, 0xdc,
+	0xe5, 0x97, 0xb6, 0xe6, 0x97, 0xe2, 0x5f, 0x2d, 0xe8, 0x3f, 0x12, 0x9c, 0x04, 0x3e, 0x49, 0x64,
+	0x4e, 0x42, 0xb5, 0xa9, 0xfc, 0x3b, 0x31, 0xdf, 0x04, 0x27, 0x64, 0xa7, 0x4c, 0x0e, 0xec, 0x65,
+	0xe4, 0x66, 0x3a, 0xfd, 0x8c, 0xf9, 0x27, 0x86, 0x02, 0xb5, 0xc4, 0x63, 0xe8, 0x16, 0x0e, 0xee,
+	0xf9, 0x27, 0xe8, 0xff, 0xe0, 0x06, 0x34, 0x64, 0x67, 0x54, 0xd0, 0xcc, 0xc7, 0x9e, 0x37, 0xdf,
+	0x50, 0x65, 0x97, 0x46, 0x82, 0x12, 0x7f, 0x4a, 0xc6, 0xa1, 0xa2, 0xa4, 0xae, 0x82, 0xca, 0x30,
+	0x4c, 0xc0, 0xfd, 0x8e, 0xc7, 0xcc, 0xff, 0x9a, 0xb3, 0x48, 0xe5, 0x41, 0x2a, 0xc1, 0x10, 0x90,
+	0x09, 0x68, 0x04, 0x8e, 0x3f, 0x65, 0x61, 0x70, 0x01, 0xcb, 0x19, 0x60, 0x9e, 0xc7, 0x56, 0x39,
+	0x8f, 0xbf, 0x5b, 0xd0, 0xd5, 0x77, 0xd4, 0x91, 0x5c, 0x5c, 0xdb, 0x2c, 0x5f, 0x5b, 0xdf, 0x56,
+	0x77, 0xc0, 0x8d, 0xd3, 0x71, 0xc8, 0x92, 0x29, 0x15, 0x03, 0xbb, 0xd6, 0xa9, 0x39, 0xa8, 0xc8,
+	0xbf, 0xb3, 0xac, 0xe8, 0xda, 0x65, 0x67, 0x9f, 0x42, 0x7f, 0x6f, 0x32, 0x11, 0x74, 0x42, 0x24,
+	0xcd, 0xbf, 0xa8, 0xaa, 0xbf, 0x45, 0x3a, 0x9b, 0xf5, 0xe9, 0xc4, 0xbf, 0x35, 0x61, 0xa3, 0x64,
+	0xc9, 0x3c, 0x5a, 0xaa, 0x1a, 0xb3, 0xf9, 0xc2, 0xf6, 0x32, 0xa1, 0xe8, 0x44, 0xcd, 0x79, 0x27,
+	0x42, 0xff, 0x83, 0xf5, 0x53, 0x16, 0xbd, 0x29, 0x82, 0xb7, 0xbc, 0xb5, 0x53, 0x16, 0xa9, 0x96,
+	0xa8, 0x55, 0xe4, 0x5d, 0xa6, 0xb2, 0x8d, 0x8a, 0xbc, 0xd3, 0xaa, 0x07, 0xb0, 0x6e, 0xba, 0xb2,
+	0x8a, 0x54, 0xa5, 0xff, 0xa6, 0x71, 0x6d, 0xd1, 0x91, 0xed, 0xd7, 0x06, 0xf5, 0x38, 0x92, 0x62,
+	0xe6, 0x15, 0x87, 0xaa, 0x25, 0xd4, 0x5e, 0xa9, 0x84, 0x86, 0xf7, 0xa0, 0xb7, 0x60, 0xb0, 0x3c,
+	0x54, 0xf4, 0x96, 0x0c, 0x15, 0xb6, 0x19, 0x2a, 0xbe, 0x6c, 0x7e, 0x61, 0xe1, 0x3f, 0x2c, 0x70,
+	0x15, 0x71, 0xdf, 0xa6, 0x54, 0xcc, 0xfe, 0x11, 0xd3, 0xe8, 0x21, 0x00, 0x91, 0x52, 0xb0, 0x71,
+	0x2a, 0xa9, 0xfa, 0xc4, 0x95, 0xd3, 0x5b, 0x25, 0xa4, 0x36, 0xbd, 0xbd, 0x57, 0x40, 0xb2, 0xa8,
+	0x4b, 0x67, 0x86, 0xf7, 0x61, 0xb3, 0xa2, 0xbe, 0x6c, 0x30, 0x72, 0xcb, 0x31, 0x7c, 0x03, 0xee,
+	0x63, 0x21, 0xb8, 0xd0, 0x63, 0xe4, 0x10, 0xd6, 0x89, 0x98, 0xa4, 0xa7, 0x34, 0x92, 0xe6, 0x74,
+	0x21, 0xaf, 0xfa, 0x5c, 0xed, 0xfe, 0xe5, 0x82, 0xb3, 0xaf, 0x74, 0xe8, 0x0e, 0xd8, 0x47, 0x2c,
+	0x9a, 0xa0, 0xbc, 0x97, 0xa9, 0xf9, 0x77, 0xb8, 0xe4, 0x20, 0x6e, 0xa0, 0x1d, 0xe8, 0x3d, 0x61,
+	0x51, 0xf0, 0xaa, 0x18, 0x24, 0xdc, 0xbc, 0xff, 0xd5, 0x9d, 0xf8, 0x1c, 0xae, 0xed, 0x87, 0x3c,
+	0xa1, 0x89, 0x3c, 0x12, 0xd4, 0xa7, 0x01, 0x8b, 0x26, 0x8a, 0xaf, 0xcb, 0x0f, 0xee, 0x40, 0xa7,
+	0xdc, 0xf0, 0x56, 0x70, 0x6e, 0x1b, 0xdc, 0xb9, 0x63, 0x2b, 0xe0, 0x3f, 0x50, 0x83, 0xbb, 0x6a,
+	0x51, 0x68, 0x89, 0x7e, 0x58, 0x36, 0x80, 0x1b, 0xe8, 0x33, 0xe8, 0x15, 0xc6, 0xf5, 0x40, 0xb3,
+	0x70, 0xc1, 0x7f, 0xcf, 0x1b, 0x50, 0x20, 0xdc, 0x50, 0xd5, 0xf5, 0x4a, 0x72, 0x41, 0x51, 0x79,
+	0x52, 0xa8, 0x1a, 0x1f, 0x81, 0xa3, 0xe7, 0x0e, 0x04, 0x66, 0xff, 0x39, 0x9d, 0x0d, 0xaf, 0x98,
+	0xf5, 0x7c, 0x22, 0xc1, 0x0d, 0x74, 0x1b, 0x5c, 0x6d, 0x4e, 0x8f, 0xc3, 0xb9, 0x15, 0x25, 0x9c,
+	0x37, 0x69, 0xeb, 0xef, 0x75, 0xc1, 0xcd, 0xdc, 0xe4, 0x7c, 0xee, 0xc5, 0x0d, 0xb4, 0x0b, 0x1b,
+	0x0b, 0x39, 0x4d, 0x50, 0xaf, 0xc8, 0x8d, 0x0a, 0x62, 0xd8, 0x2b, 0x4e, 0xa9, 0x61, 0x14, 0x37,
+	0x76, 0x2c, 0x45, 0xf5, 0x91, 0xe0, 0x63, 0xaa, 0x53, 0xb9, 0x12, 0x7b, 0xed, 0xa7, 0x3c, 0x49,
+	0x58, 0x8c, 0xae, 0x55, 0x67, 0x3e, 0x7d, 0xc5, 0xd2, 0x5d, 0xdc, 0x40, 0x5f, 0x01, 0xda, 0x4f,
+	0x85, 0xa0, 0x91, 0xac, 0xad, 0x85, 0xeb, 0x46, 0xa8, 0xce, 0x8a, 0xba, 0x84, 0x9c, 0x1f, 0x88,
+	0xa2, 0x75, 0x69, 0xae, 0x2a, 0x33, 0x9a, 0x8e, 0xeb, 0x21, 0x40, 0xb1, 0x9d, 0xa0, 0x41, 0x15,
+	0x99, 0xcf, 0x3f, 0xf5, 0x36, 0xd0, 0x6d, 0x70, 0x3c, 0x9e, 0x4a, 0x8a, 0x36, 0x0c, 0xc4, 0x34,
+	0xa4, 0x61, 0xd7, 0xc8, 0xb9, 0x73, 0xf7, 0xc0, 0x2d, 0x1a, 0x2f, 0xca, 0x83, 0xa8, 0xce, 0x0a,
+	0xc3, 0xab, 0x55, 0xc5, 0x9e, 0x7f, 0x82, 0x1b, 0xe8, 0x43, 0x70, 0x55, 0x33, 0xd5, 0x1d, 0x0f,
+	0xe5, 0x63, 0x74, 0xd1, 0x63, 0xab, 0xec, 0x7f, 0x04, 0x70, 0x48, 0xc9, 0x19, 0x5d, 0x19, 0xbe,
+	0x76, 0x94, 0x75, 0x35, 0x74, 0xb5, 0x8c, 0xcd, 0x7d, 0xaa, 0xc0, 0x3f, 0x06, 0xf7, 0x45, 0x1a,
+	0x4a, 0xa6, 0x03, 0x59, 0xe5, 0xc0, 0x7d, 0x70, 0x8b, 0x5e, 0x51, 0x44, 0x5e, 0x6d, 0x88, 0xc3,
+	0xff, 0x2c, 0x6d, 0x2b, 0xfa, 0x45, 0x71, 0x55, 0xbd, 0xbe, 0xd4, 0x8d, 0xad, 0x5f, 0x7d, 0x83,
+	0xeb, 0x3f, 0xc5, 0x47, 0xce, 0x8f, 0x2d, 0x12, 0xb3, 0x71, 0x5b, 0xff, 0xef, 0x7f, 0xf2, 0xf7,
+	0x00, 0x2c, 0xf9, 0xb6, 0xa7, 0xfe, 0x0f, 0x00, 0x00,
 }
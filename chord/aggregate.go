@@ -0,0 +1,111 @@
+package chord
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Aggregate holds ring-wide figures, combined from the nodes' own figures
+// on the way back up the broadcast tree.
+type Aggregate struct {
+	Nodes int `json:"nodes"`
+	// Keys counts the stored items, replicas included.
+	Keys int `json:"keys"`
+	// MinLoad and MaxLoad are the lowest and highest request rates.
+	MinLoad float64 `json:"min_load"`
+	MaxLoad float64 `json:"max_load"`
+	// Versions counts the nodes of each protocol version.
+	Versions map[uint32]int `json:"versions"`
+	// Unreachable are the nodes missing from the figures. For those that
+	// could not be reached, so is the rest of their arc.
+	Unreachable []*ContactInfo `json:"unreachable,omitempty"`
+}
+
+// add combines other into aggregate.
+func (aggregate *Aggregate) add(other *Aggregate) {
+	if other.Nodes > 0 {
+		if aggregate.Nodes == 0 || other.MinLoad < aggregate.MinLoad {
+			aggregate.MinLoad = other.MinLoad
+		}
+		if aggregate.Nodes == 0 || other.MaxLoad > aggregate.MaxLoad {
+			aggregate.MaxLoad = other.MaxLoad
+		}
+	}
+	aggregate.Nodes += other.Nodes
+	aggregate.Keys += other.Keys
+	if aggregate.Versions == nil {
+		aggregate.Versions = make(map[uint32]int)
+	}
+	for version, count := range other.Versions {
+		aggregate.Versions[version] += count
+	}
+	aggregate.Unreachable = append(aggregate.Unreachable, other.Unreachable...)
+}
+
+// Aggregate collects the figures of every node in the ring.
+func (peer *Peer) Aggregate(ctx context.Context) (*Aggregate, error) {
+	id := NewNodeIDFromHash(fmt.Sprintf("%s/aggregate/%d/%d", peer.GetInfo().Id.String(), peer.network.clock.Now().UnixNano(), peer.network.random(1<<30)))
+	return peer.HandleAggregate(ctx, id.String(), peer.GetInfo().Id)
+}
+
+// HandleAggregate returns the figures of the arc from the peer up to limit,
+// its own combined with those its part of the broadcast tree sends back. A
+// peer reached twice by the same query counts only once.
+func (peer *Peer) HandleAggregate(ctx context.Context, id string, limit NodeID) (aggregate *Aggregate, err error) {
+	logger.Debug("HandleAggregate: %s", id)
+
+	aggregate = &Aggregate{Versions: make(map[uint32]int)}
+	if !peer.network.seen.Add(id) {
+		return
+	}
+
+	rate := peer.load.Rate()
+	aggregate.add(&Aggregate{
+		Nodes:    1,
+		Keys:     peer.store.Len(),
+		MinLoad:  rate,
+		MaxLoad:  rate,
+		Versions: map[uint32]int{peer.GetInfo().Version: 1},
+	})
+
+	var mutex sync.Mutex
+	skipped := peer.network.fanOut(limit, CapAggregate, func(child *ContactInfo, end NodeID) {
+		res, err := peer.network.Aggregate(ctx, child, id, end)
+		mutex.Lock()
+		defer mutex.Unlock()
+		if err != nil {
+			logger.Warn("failed to aggregate through %s: %v", child.Address, err)
+			aggregate.Unreachable = append(aggregate.Unreachable, child)
+			return
+		}
+		aggregate.add(res)
+	})
+	aggregate.Unreachable = append(aggregate.Unreachable, skipped...)
+	return
+}
+
+// AggregateRing collects the figures of the ring the node at address is
+// part of, for tools outside the ring.
+func AggregateRing(address string) (*Aggregate, error) {
+	network := NewChordNetwork(&ContactInfo{Id: NewEmptyNodeID()})
+	entry, err := network.Ping(address)
+	if err != nil {
+		return nil, err
+	}
+
+	id := NewNodeIDFromHash(fmt.Sprintf("%s/aggregate/%d", address, time.Now().UnixNano()))
+	return network.Aggregate(context.Background(), entry, id.String(), entry.Id)
+}
+
+func (network *chordNetwork) Aggregate(ctx context.Context, info *ContactInfo, id string, limit NodeID) (res *Aggregate, err error) {
+	if !network.supports(info, CapAggregate) {
+		return nil, unsupported(info, "Aggregate")
+	}
+	err = network.Call(info, func(client ChordClient) error {
+		res, err = client.Aggregate(ctx, id, limit)
+		return err
+	})
+	return
+}
@@ -0,0 +1,59 @@
+package chord_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/lukaspj/go-chord/chord"
+)
+
+func TestAggregateSumsTheRing(t *testing.T) {
+	const n, keys = 6, 10
+	ring := startRing(t, n, nil)
+	defer ring.Stop()
+
+	// Every replica is written before the put returns
+	quorum := chord.Quorum{N: 3, R: 1, W: 3}
+	for i := 0; i < keys; i++ {
+		if err := ring.Peers[0].PutWithQuorum(context.Background(), fmt.Sprintf("key %d", i), []byte("value"), quorum); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	res, err := chord.AggregateRing(ring.Peers[n-1].GetInfo().Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Nodes != n || res.Keys != keys*quorum.N || res.Versions[chord.ProtocolVersion] != n {
+		t.Errorf("aggregate counted %d nodes, %d keys and %v versions, expected %d, %d and %d of version %d",
+			res.Nodes, res.Keys, res.Versions, n, keys*quorum.N, n, chord.ProtocolVersion)
+	}
+	if len(res.Unreachable) > 0 {
+		t.Errorf("aggregate could not reach %d nodes in a healthy ring", len(res.Unreachable))
+	}
+}
+
+func TestAggregateReportsWhatItMissed(t *testing.T) {
+	const n = 6
+	started := 0
+	ring := startRing(t, n, func(peer *chord.Peer) {
+		if started == 2 {
+			peer.Capabilities &^= chord.CapAggregate
+		}
+		started++
+	})
+	defer ring.Stop()
+	old := ring.Peers[2]
+
+	res, err := ring.Peers[0].Aggregate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Nodes != n-1 {
+		t.Errorf("aggregate counted %d nodes, expected all but the one without aggregation", res.Nodes)
+	}
+	if len(res.Unreachable) != 1 || res.Unreachable[0].Address != old.GetInfo().Address {
+		t.Errorf("aggregate reported %v as unreachable, expected only %s", res.Unreachable, old.GetInfo().Address)
+	}
+}
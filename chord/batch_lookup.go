@@ -0,0 +1,75 @@
+package chord
+
+import (
+	"context"
+	"sync"
+)
+
+// FindSuccessors resolves many ids at once. The ids this node can answer
+// directly are answered, the rest are split by the finger that is closest
+// to them and forwarded as one sub-batch per finger, so a whole batch costs
+// about as many round-trips as a single lookup.
+func (peer *Peer) FindSuccessors(ctx context.Context, ids []NodeID) (res []*ContactInfo, err error) {
+	logger.Debug("FindSuccessors: %d ids", len(ids))
+
+	successor := peer.network.successors.GetSuccessor(0)
+	res = make([]*ContactInfo, len(ids))
+
+	type subBatch struct {
+		next    *ContactInfo
+		ids     []NodeID
+		indices []int
+	}
+	batches := make(map[string]*subBatch)
+	var order []*subBatch
+
+	for i, id := range ids {
+		if id.Between(peer.GetInfo().Id, successor.Id) {
+			res[i] = successor
+			continue
+		}
+
+		next, _ := peer.ClosestPrecedingNode(ctx, &id)
+		if next.Id.Equals(peer.GetInfo().Id) {
+			next = successor
+		}
+
+		key := next.Id.String()
+		if batches[key] == nil {
+			batches[key] = &subBatch{next: next}
+			order = append(order, batches[key])
+		}
+		batches[key].ids = append(batches[key].ids, id)
+		batches[key].indices = append(batches[key].indices, i)
+	}
+
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	for _, batch := range order {
+		batch := batch
+		wg.Add(1)
+		peer.network.async(func() {
+			defer wg.Done()
+
+			found, e := peer.network.FindSuccessors(batch.next, batch.ids)
+			if e != nil {
+				logger.Warn("forwarding %d ids to %s failed, resolving them one by one: %v", len(batch.ids), batch.next.Address, e)
+				found = make([]*ContactInfo, len(batch.ids))
+				for j := range batch.ids {
+					if found[j], e = peer.FindSuccessor(ctx, &batch.ids[j]); e != nil {
+						mutex.Lock()
+						err = e
+						mutex.Unlock()
+					}
+				}
+			}
+
+			for j, index := range batch.indices {
+				res[index] = found[j]
+			}
+		})
+	}
+	wg.Wait()
+
+	return
+}
@@ -0,0 +1,121 @@
+package chord_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/lukaspj/go-chord/chord"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const findSuccessorsMethod = "/chord.Chord/FindSuccessors"
+
+// batchRecorder records the batches a node forwards, and can refuse them to
+// force the one by one fallback.
+type batchRecorder struct {
+	mutex   sync.Mutex
+	from    string
+	targets map[string]int
+	refuse  bool
+}
+
+func (recorder *batchRecorder) transport(from string) chord.Transport {
+	return batchTransport{recorder: recorder, from: from}
+}
+
+func (recorder *batchRecorder) record(from, to string) error {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	if from != recorder.from {
+		return nil
+	}
+	recorder.targets[to]++
+	if recorder.refuse {
+		return status.Errorf(codes.Unavailable, "batch from %s to %s refused", from, to)
+	}
+	return nil
+}
+
+type batchTransport struct {
+	recorder *batchRecorder
+	from     string
+}
+
+func (t batchTransport) Dial(contact *chord.ContactInfo) (*grpc.ClientConn, error) {
+	return grpc.Dial(contact.Address, grpc.WithInsecure(),
+		grpc.WithStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			if method == findSuccessorsMethod {
+				if err := t.recorder.record(t.from, contact.Address); err != nil {
+					return nil, err
+				}
+			}
+			return streamer(ctx, desc, cc, method, opts...)
+		}))
+}
+
+func TestBatchLookup(t *testing.T) {
+	recorder := &batchRecorder{}
+	ring := startRing(t, 8, func(peer *chord.Peer) {
+		peer.Transport = recorder.transport(peer.GetInfo().Address)
+	})
+	defer ring.Stop()
+
+	ctx := context.Background()
+	peer := ring.Running()[0]
+	var ids []chord.NodeID
+	for i := 0; i < 32; i++ {
+		ids = append(ids, chord.NewNodeIDFromHash(fmt.Sprintf("batch-%d", i)))
+	}
+
+	// The ids are split by the node each would be forwarded to
+	expected := map[string]int{}
+	for _, id := range ids {
+		if id.Between(peer.GetInfo().Id, peer.GetSuccessor().Id) {
+			continue
+		}
+		next, _ := peer.ClosestPrecedingNode(ctx, &id)
+		if next.Id.Equals(peer.GetInfo().Id) {
+			next = peer.GetSuccessor()
+		}
+		expected[next.Address] = 1
+	}
+	if len(expected) < 2 {
+		t.Fatalf("the ids are forwarded to %v, expected them to be split", expected)
+	}
+
+	for _, refuse := range []bool{false, true} {
+		recorder.mutex.Lock()
+		recorder.from = peer.GetInfo().Address
+		recorder.targets = map[string]int{}
+		recorder.refuse = refuse
+		recorder.mutex.Unlock()
+
+		found, err := peer.FindSuccessors(ctx, ids)
+		if err != nil {
+			t.Fatalf("batch lookup failed: %v", err)
+		}
+		for i, id := range ids {
+			single, err := peer.FindSuccessor(ctx, &id)
+			if err != nil {
+				t.Fatalf("lookup of %s failed: %v", id.String(), err)
+			}
+			if found[i] == nil || found[i].Address != single.Address {
+				t.Errorf("batch found %v for %s, one by one found %s", found[i], id.String(), single.Address)
+			}
+			if responsible := ring.Responsible(id).GetInfo(); single.Address != responsible.Address {
+				t.Errorf("lookup of %s found %s, expected %s", id.String(), single.Address, responsible.Address)
+			}
+		}
+
+		recorder.mutex.Lock()
+		targets := recorder.targets
+		recorder.mutex.Unlock()
+		if fmt.Sprint(targets) != fmt.Sprint(expected) {
+			t.Errorf("batches were sent to %v, expected one to each of %v", targets, expected)
+		}
+	}
+}
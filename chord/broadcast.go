@@ -0,0 +1,210 @@
+package chord
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Broadcasts are remembered this long to drop the copies that reach a node
+// twice while the ring is changing.
+const broadcastMemory = 10 * time.Minute
+
+// Broadcast is a message sent to every node in the ring.
+type Broadcast struct {
+	Id      string
+	Payload []byte
+	Origin  *ContactInfo
+	// Limit ends the arc the receiving node passes the message on in, the
+	// arc runs from the node up to, but not including, Limit.
+	Limit NodeID
+	// Ack is set when the sender waits for the whole arc to be covered.
+	Ack bool
+}
+
+// BroadcastAck sums up how far a broadcast got.
+type BroadcastAck struct {
+	// Delivered counts the nodes that handled the broadcast.
+	Delivered int
+	// Unreachable are the nodes it could not be passed on to, or that do
+	// not handle broadcasts. The rest of the arc an unreachable node was to
+	// cover has not been reached either.
+	Unreachable []*ContactInfo
+}
+
+func (ack *BroadcastAck) add(other *BroadcastAck) {
+	ack.Delivered += other.Delivered
+	ack.Unreachable = append(ack.Unreachable, other.Unreachable...)
+}
+
+// BroadcastHandler handles the broadcasts that reach the peer.
+type BroadcastHandler func(ctx context.Context, b *Broadcast)
+
+// seenSet remembers the broadcasts a node has handled.
+type seenSet struct {
+	mutex sync.Mutex
+	clock Clock
+	seen  map[string]time.Time
+}
+
+func newSeenSet() *seenSet {
+	return &seenSet{clock: realClock{}, seen: make(map[string]time.Time)}
+}
+
+// Add records id and tells whether it is new.
+func (set *seenSet) Add(id string) bool {
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+
+	now := set.clock.Now()
+	for seen, at := range set.seen {
+		if now.Sub(at) > broadcastMemory {
+			delete(set.seen, seen)
+		}
+	}
+	if _, ok := set.seen[id]; ok {
+		return false
+	}
+	set.seen[id] = now
+	return true
+}
+
+// Broadcast sends payload to every node in the ring, this one included.
+// Each node passes it on to the nodes it knows of, each of which covers the
+// arc up to the next one, so the message spreads in O(log N) rounds and
+// reaches every node once. With ack the call returns when the whole ring has
+// been covered, and otherwise as soon as the message is on its way, with a
+// nil ack.
+func (peer *Peer) Broadcast(ctx context.Context, payload []byte, ack bool) (*BroadcastAck, error) {
+	id := NewNodeIDFromHash(fmt.Sprintf("%s/%d/%d", peer.GetInfo().Id.String(), peer.network.clock.Now().UnixNano(), peer.network.random(1<<30)))
+	res, err := peer.HandleBroadcast(ctx, &Broadcast{
+		Id:      id.String(),
+		Payload: payload,
+		Origin:  peer.GetInfo(),
+		Limit:   peer.GetInfo().Id,
+		Ack:     ack,
+	})
+	if !ack {
+		res = nil
+	}
+	return res, err
+}
+
+// HandleBroadcast delivers b to the peer's handler and passes it on in the
+// peer's arc, unless the peer has seen it before.
+func (peer *Peer) HandleBroadcast(ctx context.Context, b *Broadcast) (ack *BroadcastAck, err error) {
+	logger.Debug("HandleBroadcast: %s", b.Id)
+
+	ack = &BroadcastAck{}
+	if !peer.network.seen.Add(b.Id) {
+		return
+	}
+	ack.Delivered = 1
+	if handler := peer.BroadcastHandler; handler != nil {
+		handler(ctx, b)
+	}
+
+	if b.Ack {
+		ack.add(peer.network.spread(ctx, b))
+	} else {
+		peer.network.async(func() { peer.network.spread(context.Background(), b) })
+	}
+	return
+}
+
+// spread passes b on to every node we know of in (us, b.Limit), each
+// covering the arc up to the next one.
+func (network *chordNetwork) spread(ctx context.Context, b *Broadcast) *BroadcastAck {
+	ack := &BroadcastAck{}
+	var mutex sync.Mutex
+	skipped := network.fanOut(b.Limit, CapBroadcast, func(child *ContactInfo, limit NodeID) {
+		next := *b
+		next.Limit = limit
+		res, err := network.Broadcast(ctx, child, &next)
+		mutex.Lock()
+		defer mutex.Unlock()
+		if err != nil {
+			logger.Warn("failed to pass broadcast %s on to %s: %v", b.Id, child.Address, err)
+			ack.Unreachable = append(ack.Unreachable, child)
+			return
+		}
+		ack.add(res)
+	})
+	ack.Unreachable = append(ack.Unreachable, skipped...)
+	return ack
+}
+
+// fanOut calls visit, concurrently, for every node with capability we know
+// of in (us, limit), with the end of the arc that node is to cover. It
+// returns when every call has. A node without capability lies in the arc of
+// the node before it, which passes over it in turn. Those before the first
+// node with capability are returned as skipped.
+func (network *chordNetwork) fanOut(limit NodeID, capability Capability, visit func(child *ContactInfo, limit NodeID)) (skipped []*ContactInfo) {
+	var children []*ContactInfo
+	for _, target := range network.broadcastTargets(limit) {
+		switch {
+		case network.supports(target, capability):
+			children = append(children, target)
+		case len(children) == 0:
+			skipped = append(skipped, target)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i, child := range children {
+		end := limit
+		if i+1 < len(children) {
+			end = children[i+1].Id
+		}
+
+		child, end := child, end
+		wg.Add(1)
+		network.async(func() {
+			defer wg.Done()
+			visit(child, end)
+		})
+	}
+	wg.Wait()
+	return
+}
+
+// broadcastTargets returns the fingers and successors in (us, limit),
+// ordered along the ring.
+func (network *chordNetwork) broadcastTargets(limit NodeID) (targets []*ContactInfo) {
+	self := network.self().Id
+	known := make(map[string]bool)
+	consider := func(c *ContactInfo) {
+		if c == nil || c.Id.IsZero() || c.Id.Equals(self) || c.Id.Equals(limit) || known[c.Id.String()] {
+			return
+		}
+		if !c.Id.Between(self, limit) {
+			return
+		}
+		known[c.Id.String()] = true
+		targets = append(targets, c)
+	}
+	for _, finger := range network.fingerTable.Fingers() {
+		consider(finger)
+	}
+	for _, succ := range network.successors.List() {
+		consider(succ)
+	}
+
+	sort.Slice(targets, func(i, j int) bool {
+		return self.Distance(targets[i].Id).Cmp(self.Distance(targets[j].Id)) < 0
+	})
+	return
+}
+
+func (network *chordNetwork) Broadcast(ctx context.Context, info *ContactInfo, b *Broadcast) (res *BroadcastAck, err error) {
+	if !network.supports(info, CapBroadcast) {
+		return nil, unsupported(info, "Broadcast")
+	}
+	err = network.Call(info, func(client ChordClient) error {
+		res, err = client.Broadcast(ctx, b)
+		return err
+	})
+	return
+}
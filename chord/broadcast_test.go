@@ -0,0 +1,93 @@
+package chord_test
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/lukaspj/go-chord/chord"
+	"github.com/lukaspj/go-chord/chordtest"
+)
+
+func TestBroadcastReachesEveryNodeOnce(t *testing.T) {
+	var mutex sync.Mutex
+	received := make(map[*chord.Peer]int)
+	ring := startRing(t, 8, func(peer *chord.Peer) {
+		peer.BroadcastHandler = func(ctx context.Context, b *chord.Broadcast) {
+			if !bytes.Equal(b.Payload, []byte("hello")) {
+				t.Errorf("%s received %q", peer.GetInfo().Address, b.Payload)
+			}
+			mutex.Lock()
+			defer mutex.Unlock()
+			received[peer]++
+		}
+	})
+	defer ring.Stop()
+
+	// The ring has converged with every finger in place, so the broadcast
+	// fans out through them
+	chordtest.RingIsConsistent(t, ring)
+
+	ack, err := ring.Peers[0].Broadcast(context.Background(), []byte("hello"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ack.Delivered != len(ring.Peers) || len(ack.Unreachable) > 0 {
+		t.Errorf("broadcast was delivered to %d nodes and could not reach %d, expected %d and none", ack.Delivered, len(ack.Unreachable), len(ring.Peers))
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	for _, peer := range ring.Peers {
+		if received[peer] != 1 {
+			t.Errorf("%s handled the broadcast %d times, expected once", peer.GetInfo().Address, received[peer])
+		}
+	}
+}
+
+func TestBroadcastReportsNodesWithoutBroadcast(t *testing.T) {
+	var mutex sync.Mutex
+	received := make(map[*chord.Peer]int)
+	started := 0
+	ring := startRing(t, 8, func(peer *chord.Peer) {
+		// Every third node does not handle broadcasts
+		if started%3 == 1 {
+			peer.Capabilities &^= chord.CapBroadcast
+		}
+		started++
+		peer.BroadcastHandler = func(ctx context.Context, b *chord.Broadcast) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			received[peer]++
+		}
+	})
+	defer ring.Stop()
+
+	ack, err := ring.Peers[0].Broadcast(context.Background(), []byte("hello"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unreachable := make(map[string]int)
+	for _, node := range ack.Unreachable {
+		unreachable[node.Address]++
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	delivered := 0
+	for _, peer := range ring.Peers {
+		address := peer.GetInfo().Address
+		if peer.GetInfo().Capabilities.Has(chord.CapBroadcast) {
+			delivered++
+			if received[peer] != 1 || unreachable[address] > 0 {
+				t.Errorf("%s handled the broadcast %d times and was reported %d times, expected once and never", address, received[peer], unreachable[address])
+			}
+		} else if received[peer] != 0 || unreachable[address] != 1 {
+			t.Errorf("%s without broadcast handled it %d times and was reported %d times, expected never and once", address, received[peer], unreachable[address])
+		}
+	}
+	if ack.Delivered != delivered {
+		t.Errorf("broadcast was delivered to %d nodes, expected %d", ack.Delivered, delivered)
+	}
+}
@@ -0,0 +1,147 @@
+package chord
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/grpc"
+)
+
+// ProtocolVersion is the version of the wire protocol this package speaks.
+// Peers from before versioning report version 0 and no capabilities.
+const ProtocolVersion = 1
+
+// Capability is a set of optional RPCs. A peer advertises the ones it
+// serves in its ContactInfo, and only advertised ones are called, so that
+// peers of different versions can share a ring.
+type Capability uint64
+
+const (
+	CapSuccessorList Capability = 1 << iota
+	// CapStorage covers Store, Fetch and StoreHint.
+	CapStorage
+	CapLoad
+	CapBatchLookup
+	CapProbe
+	CapGossip
+	// CapExplicitPredecessor is CurrentPredecessor, which tells "no
+	// predecessor" apart from an empty reply.
+	CapExplicitPredecessor
+	CapWatch
+	// CapNeighbours is the RPC that makes a stabilization round a single
+	// call.
+	CapNeighbours
+	CapRoute
+	CapBroadcast
+	// CapPubSub covers JoinTopic, LeaveTopic, Publish and Multicast.
+	CapPubSub
+	CapAggregate
+	CapDiscovery
+)
+
+const AllCapabilities = CapSuccessorList | CapStorage | CapLoad | CapBatchLookup |
+	CapProbe | CapGossip | CapExplicitPredecessor | CapWatch | CapNeighbours | CapRoute | CapBroadcast | CapPubSub | CapAggregate | CapDiscovery
+
+// methodCapabilities maps the optional RPCs to the capability that covers
+// them, every RPC not listed is part of the base protocol.
+var methodCapabilities = map[string]Capability{
+	"/chord.Chord/SuccessorList":      CapSuccessorList,
+	"/chord.Chord/Store":              CapStorage,
+	"/chord.Chord/Fetch":              CapStorage,
+	"/chord.Chord/StoreHint":          CapStorage,
+	"/chord.Chord/Load":               CapLoad,
+	"/chord.Chord/FindSuccessors":     CapBatchLookup,
+	"/chord.Chord/ProbeNode":          CapProbe,
+	"/chord.Chord/Gossip":             CapGossip,
+	"/chord.Chord/CurrentPredecessor": CapExplicitPredecessor,
+	"/chord.Chord/Watch":              CapWatch,
+	"/chord.Chord/Neighbours":         CapNeighbours,
+	"/chord.Chord/Route":              CapRoute,
+	"/chord.Chord/Broadcast":          CapBroadcast,
+	"/chord.Chord/JoinTopic":          CapPubSub,
+	"/chord.Chord/LeaveTopic":         CapPubSub,
+	"/chord.Chord/Publish":            CapPubSub,
+	"/chord.Chord/Multicast":          CapPubSub,
+	"/chord.Chord/Aggregate":          CapAggregate,
+	"/chord.Chord/FindNodes":          CapDiscovery,
+}
+
+func (capabilities Capability) Has(capability Capability) bool {
+	return capabilities&capability == capability
+}
+
+// capabilityTable remembers what each address said about itself when it
+// was pinged. ContactInfo passed on by an older peer loses the fields, so
+// what a node says directly is kept apart.
+type capabilityTable struct {
+	mutex   sync.RWMutex
+	entries map[string]Capability
+}
+
+func newCapabilityTable() *capabilityTable {
+	return &capabilityTable{entries: make(map[string]Capability)}
+}
+
+func (table *capabilityTable) Learn(info *ContactInfo) {
+	table.mutex.Lock()
+	defer table.mutex.Unlock()
+	table.entries[info.Address] = info.Capabilities
+}
+
+func (table *capabilityTable) Get(address string) (capabilities Capability, ok bool) {
+	table.mutex.RLock()
+	defer table.mutex.RUnlock()
+	capabilities, ok = table.entries[address]
+	return
+}
+
+// supports tells whether contact serves the RPCs of capability, and we call
+// them. Nodes we know nothing about are assumed to speak only the base
+// protocol.
+func (network *chordNetwork) supports(contact *ContactInfo, capability Capability) bool {
+	if !network.enabled.Has(capability) {
+		return false
+	}
+	if contact.Version > 0 {
+		return contact.Capabilities.Has(capability)
+	}
+	capabilities, _ := network.capabilities.Get(contact.Address)
+	return capabilities.Has(capability)
+}
+
+func unsupported(contact *ContactInfo, method string) *Error {
+	return &Error{Kind: Unsupported, Node: contact, Message: method + " is not supported"}
+}
+
+// capabilityUnaryInterceptor refuses the RPCs the called peer does not
+// advertise, the way a peer that predates them would.
+func capabilityUnaryInterceptor(capabilities func(ctx context.Context) Capability) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if required, ok := methodCapabilities[info.FullMethod]; ok && !capabilities(ctx).Has(required) {
+			return nil, newError(Unsupported, "%s is not supported", info.FullMethod)
+		}
+		return handler(ctx, req)
+	}
+}
+
+func capabilityStreamInterceptor(capabilities func(ctx context.Context) Capability) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if required, ok := methodCapabilities[info.FullMethod]; ok && !capabilities(ss.Context()).Has(required) {
+			return newError(Unsupported, "%s is not supported", info.FullMethod)
+		}
+		return handler(srv, ss)
+	}
+}
+
+// serverOptions installs what every chord server runs its RPCs through: the
+// faults, if any, and the refusal of what the called peer does not
+// advertise.
+func serverOptions(faults *Faults, advertised func(ctx context.Context) Capability) []grpc.ServerOption {
+	unary := capabilityUnaryInterceptor(advertised)
+	stream := capabilityStreamInterceptor(advertised)
+	if faults != nil {
+		unary = chainUnaryServer(faults.UnaryServerInterceptor(), unary)
+		stream = chainStreamServer(faults.StreamServerInterceptor(), stream)
+	}
+	return []grpc.ServerOption{grpc.UnaryInterceptor(unary), grpc.StreamInterceptor(stream)}
+}
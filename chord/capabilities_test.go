@@ -0,0 +1,113 @@
+package chord_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/lukaspj/go-chord/api"
+	"github.com/lukaspj/go-chord/chord"
+	"github.com/lukaspj/go-chord/chordtest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// baseMethods are the RPCs of the protocol from before versioning.
+var baseMethods = map[string]bool{
+	"/chord.Chord/Ping":                 true,
+	"/chord.Chord/FindSuccessor":        true,
+	"/chord.Chord/ClosestPrecedingNode": true,
+	"/chord.Chord/Predecessor":          true,
+	"/chord.Chord/Successor":            true,
+	"/chord.Chord/Notify":               true,
+}
+
+// predate makes a peer answer like one built before versions and
+// capabilities were advertised.
+func predate(peer *chord.Peer) {
+	peer.Version = 0
+	peer.Capabilities = 0
+}
+
+// predateClient makes a peer also call others like an old one: only the
+// base RPCs, without the target metadata and without the fields added to
+// ContactInfo since.
+func predateClient(peer *chord.Peer) {
+	predate(peer)
+	peer.Transport = oldTransport{}
+}
+
+type oldTransport struct{}
+
+func (oldTransport) Dial(contact *chord.ContactInfo) (*grpc.ClientConn, error) {
+	return grpc.Dial(contact.Address, grpc.WithInsecure(),
+		grpc.WithUnaryInterceptor(func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			if !baseMethods[method] {
+				return status.Errorf(codes.Unimplemented, "an old peer does not know %s", method)
+			}
+			if info, ok := req.(*api.ContactInfo); ok {
+				req = &api.ContactInfo{Address: info.Address, Id: info.Id, Payload: info.Payload}
+			}
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}),
+		grpc.WithStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return nil, status.Errorf(codes.Unimplemented, "an old peer does not know %s", method)
+		}))
+}
+
+func TestMixedVersionRing(t *testing.T) {
+	joined := 0
+	ring := startRing(t, 6, func(peer *chord.Peer) {
+		if joined%2 == 1 {
+			predateClient(peer)
+		}
+		joined++
+	})
+	defer ring.Stop()
+
+	// An old host. Old clients cannot name a virtual node, so it serves one
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := l.Addr().String()
+	host := chord.NewHost(&chord.ContactInfo{Address: address, Id: chord.NewNodeIDFromHash(address)}, l.Addr().(*net.TCPAddr).Port, 1)
+	for _, peer := range host.Peers {
+		predate(peer)
+	}
+	host.Serve(l)
+	host.Start()
+	defer host.Stop()
+	if err = host.Connect(ring.Peers[0].GetInfo().Address); err != nil {
+		t.Fatal(err)
+	}
+	ring.Peers = append(ring.Peers, host.Peers...)
+
+	if err = ring.WaitForConvergence(convergenceTimeout); err != nil {
+		t.Fatal(err)
+	}
+
+	// Old peers store nothing, the items go through the new ones
+	var current []*chord.Peer
+	for _, peer := range ring.Peers {
+		if peer.Version > 0 {
+			current = append(current, peer)
+		}
+	}
+	items := make(map[string][]byte)
+	var keys []string
+	for i := 0; i < 10; i++ {
+		key := fmt.Sprintf("mixed-%d", i)
+		keys = append(keys, key)
+		items[key] = []byte(fmt.Sprintf("value-%d", i))
+		if err = current[i%len(current)].Put(context.Background(), key, items[key]); err != nil {
+			t.Fatalf("put of %s through %s failed: %v", key, current[i%len(current)].GetInfo().Address, err)
+		}
+	}
+
+	chordtest.RingIsConsistent(t, ring)
+	chordtest.LookupsAgree(t, ring, keys...)
+	chordtest.AllKeysPresent(t, &chordtest.Ring{Peers: current}, items)
+}
@@ -0,0 +1,162 @@
+package chord
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// Walks of more nodes than this are assumed to be stuck in a loop.
+const maxCheckedRingSize = 100000
+
+// Inconsistency is a single problem found by CheckRing.
+type Inconsistency struct {
+	Node    *ContactInfo `json:"node"`
+	Problem string       `json:"problem"`
+}
+
+func (inc Inconsistency) String() string {
+	return fmt.Sprintf("%s (%s): %s", inc.Node.Id.String(), inc.Node.Address, inc.Problem)
+}
+
+// CheckRing walks the ring from the node at address using only the public
+// RPCs, and reports every place where it is not well-formed: successor
+// pointers must form a single cycle in id order, every node's successor must
+// have it as predecessor, every finger must point at the successor of its
+// start, and lookups of every finger start must agree with the ring that was
+// walked.
+func CheckRing(address string) (nodes []*ContactInfo, problems []Inconsistency, err error) {
+	network := NewChordNetwork(&ContactInfo{Id: NewEmptyNodeID()})
+
+	var entry *ContactInfo
+	if entry, err = network.Ping(address); err != nil {
+		return
+	}
+	if entry == nil {
+		err = newError(Internal, "%s did not identify itself", address)
+		return
+	}
+
+	report := func(node *ContactInfo, format string, args ...interface{}) {
+		problems = append(problems, Inconsistency{Node: node, Problem: fmt.Sprintf(format, args...)})
+	}
+
+	// Successor pointers must lead back to the entry, wrapping around the
+	// id space exactly once on the way
+	seen := map[string]bool{entry.Id.String(): true}
+	nodes = append(nodes, entry)
+	wraps := 0
+	for current := entry; ; {
+		succ, e := network.Successor(current)
+		if e != nil {
+			report(current, "successor could not be fetched: %v", e)
+			break
+		}
+		if succ == nil {
+			report(current, "has no successor")
+			break
+		}
+		if !current.Id.Less(succ.Id) {
+			wraps++
+		}
+		if succ.Id.Equals(entry.Id) {
+			break
+		}
+		if seen[succ.Id.String()] {
+			report(current, "successor %s loops back without reaching %s", succ.Id.String(), entry.Id.String())
+			break
+		}
+		if len(nodes) >= maxCheckedRingSize {
+			report(current, "gave up after walking %d nodes", len(nodes))
+			break
+		}
+		seen[succ.Id.String()] = true
+		nodes = append(nodes, succ)
+		current = succ
+	}
+	if wraps > 1 {
+		report(entry, "successor pointers wrap around the id space %d times, they are not in id order", wraps)
+	}
+
+	// Every successor must agree on who its predecessor is
+	for i, node := range nodes {
+		succ := nodes[(i+1)%len(nodes)]
+		pred, e := network.Predecessor(succ)
+		switch {
+		case e != nil:
+			report(succ, "predecessor could not be fetched: %v", e)
+		case pred == nil:
+			report(succ, "has no predecessor, expected %s", node.Id.String())
+		case !pred.Id.Equals(node.Id):
+			report(succ, "has predecessor %s, but %s has it as successor", pred.Id.String(), node.Id.String())
+		}
+	}
+
+	// Each node's fingers must point at the successors of their starts. A
+	// node answers ClosestPrecedingNode of the id just past a finger with
+	// that finger, unless its table lacks it
+	sorted := append([]*ContactInfo(nil), nodes...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Id.Less(sorted[j].Id)
+	})
+	for _, node := range nodes {
+		var previous *ContactInfo
+		for i := 0; i < fingerCount; i++ {
+			expected := responsibleNode(sorted, node.Id.FingerStart(i))
+			// Fingers with the same successor are checked once
+			if expected == previous || expected.Id.Equals(node.Id) {
+				continue
+			}
+			previous = expected
+			found, e := network.ClosestPrecedingNode(node, justPast(expected.Id))
+			switch {
+			case e != nil:
+				report(node, "finger %d could not be fetched: %v", i, e)
+			case found == nil:
+				report(node, "finger %d is missing, expected %s", i, expected.Id.String())
+			case !found.Id.Equals(expected.Id):
+				report(node, "finger %d is %s, expected %s", i, found.Id.String(), expected.Id.String())
+			}
+		}
+	}
+
+	// And lookups through each node must find the node the walk says is
+	// responsible
+	for _, node := range nodes {
+		var previous *ContactInfo
+		for i := 0; i < fingerCount; i++ {
+			start := node.Id.FingerStart(i)
+			expected := responsibleNode(sorted, start)
+			if expected == previous {
+				continue
+			}
+			previous = expected
+			found, e := network.FindSuccessor(node, start)
+			switch {
+			case e != nil:
+				report(node, "lookup of finger %d failed: %v", i, e)
+			case found == nil:
+				report(node, "lookup of finger %d found nothing, expected %s", i, expected.Id.String())
+			case !found.Id.Equals(expected.Id):
+				report(node, "lookup of finger %d found %s, expected %s", i, found.Id.String(), expected.Id.String())
+			}
+		}
+	}
+
+	return
+}
+
+// justPast returns the id following id on the ring.
+func justPast(id NodeID) NodeID {
+	val := big.NewInt(0).Add(id.BigInt(), big.NewInt(1))
+	val.Mod(val, ringModulus())
+	return NodeID{Val: val.Bytes()}
+}
+
+// responsibleNode returns the first node at or after id in the sorted list.
+func responsibleNode(sorted []*ContactInfo, id NodeID) *ContactInfo {
+	i := sort.Search(len(sorted), func(i int) bool {
+		return !sorted[i].Id.Less(id)
+	})
+	return sorted[i%len(sorted)]
+}
@@ -0,0 +1,96 @@
+package chord
+
+import (
+	"net"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// wiredRing serves n peers without maintenance, and sets their routing state
+// to that of a converged ring, so a test can corrupt it.
+func wiredRing(t *testing.T, n int) (peers []*Peer) {
+	for i := 0; i < n; i++ {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		address := l.Addr().String()
+		p := NewPeer(&ContactInfo{Address: address, Id: NewNodeIDFromHash(address)}, 0)
+		peer := &p
+		peer.Reset()
+		peer.Serve(l)
+		peers = append(peers, peer)
+	}
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].GetInfo().Id.Less(peers[j].GetInfo().Id)
+	})
+
+	var sorted []*ContactInfo
+	for _, peer := range peers {
+		sorted = append(sorted, peer.GetInfo())
+	}
+	for i, peer := range peers {
+		peer.network.setPredecessor(sorted[(i+n-1)%n])
+		for j := 0; j < successorListSize; j++ {
+			peer.network.successors.SetSuccessor(j, sorted[(i+j+1)%n])
+		}
+		for f := 0; f < fingerCount; f++ {
+			peer.network.fingerTable.SetFinger(f, responsibleNode(sorted, peer.GetInfo().Id.FingerStart(f)))
+		}
+	}
+	return
+}
+
+func stopAll(peers []*Peer) {
+	for _, peer := range peers {
+		peer.Stop()
+	}
+}
+
+func TestCheckHealthyRing(t *testing.T) {
+	peers := wiredRing(t, 4)
+	defer stopAll(peers)
+
+	nodes, problems, err := CheckRing(peers[0].GetInfo().Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != len(peers) {
+		t.Errorf("walk found %d nodes, expected %d", len(nodes), len(peers))
+	}
+	for _, problem := range problems {
+		t.Errorf("healthy ring reported: %s", problem)
+	}
+}
+
+func TestCheckCorruptedRing(t *testing.T) {
+	peers := wiredRing(t, 4)
+	defer stopAll(peers)
+
+	// The top finger of the first peer points at a node that has left
+	gone := &ContactInfo{Address: "gone", Id: justPast(peers[0].GetInfo().Id)}
+	peers[0].network.fingerTable.SetFinger(fingerCount-1, gone)
+	// And the second peer has lost its predecessor
+	peers[1].network.setPredecessor(nil)
+
+	_, problems, err := CheckRing(peers[0].GetInfo().Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fingers, predecessors int
+	for _, problem := range problems {
+		switch {
+		case problem.Node.Id.Equals(peers[0].GetInfo().Id) && strings.Contains(problem.Problem, "is "+gone.Id.String()):
+			fingers++
+		case problem.Node.Id.Equals(peers[1].GetInfo().Id) && strings.Contains(problem.Problem, "has no predecessor"):
+			predecessors++
+		}
+	}
+	if fingers == 0 {
+		t.Errorf("the wrong finger was not reported in %v", problems)
+	}
+	if predecessors != 1 {
+		t.Errorf("the missing predecessor was reported %d times in %v", predecessors, problems)
+	}
+}
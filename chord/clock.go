@@ -0,0 +1,143 @@
+package chord
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells the protocol what time it is and when to run its periodic
+// functions, so that it can also be run on virtual time.
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is the part of time.Timer a Clock has to provide.
+type Timer interface {
+	C() <-chan time.Time
+	Reset(d time.Duration) bool
+	Stop() bool
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+type realTimer struct {
+	*time.Timer
+}
+
+func (timer realTimer) C() <-chan time.Time {
+	return timer.Timer.C
+}
+
+// FakeClock is a Clock that only moves when it is told to, for tests that
+// would otherwise have to sleep through back-off periods.
+type FakeClock struct {
+	mutex  sync.Mutex
+	cond   *sync.Cond
+	now    time.Time
+	timers map[*fakeTimer]bool
+}
+
+func NewFakeClock(now time.Time) *FakeClock {
+	clock := &FakeClock{
+		now:    now,
+		timers: make(map[*fakeTimer]bool),
+	}
+	clock.cond = sync.NewCond(&clock.mutex)
+	return clock
+}
+
+func (clock *FakeClock) Now() time.Time {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+	return clock.now
+}
+
+func (clock *FakeClock) NewTimer(d time.Duration) Timer {
+	timer := &fakeTimer{clock: clock, c: make(chan time.Time, 1)}
+	timer.Reset(d)
+	return timer
+}
+
+// Advance moves the clock forward by d and fires every timer that is due.
+func (clock *FakeClock) Advance(d time.Duration) {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+	clock.set(clock.now.Add(d))
+}
+
+// Set moves the clock forward to t, it never goes back in time.
+func (clock *FakeClock) Set(t time.Time) {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+	if t.After(clock.now) {
+		clock.set(t)
+	}
+}
+
+func (clock *FakeClock) set(t time.Time) {
+	clock.now = t
+	for timer := range clock.timers {
+		if !timer.deadline.After(t) {
+			delete(clock.timers, timer)
+			select {
+			case timer.c <- t:
+			default:
+			}
+		}
+	}
+	clock.cond.Broadcast()
+}
+
+// BlockUntil waits until n timers are pending. A test calls it before
+// Advance, so that the functions woken by the previous Advance have
+// finished and set their next timer.
+func (clock *FakeClock) BlockUntil(n int) {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+	for len(clock.timers) < n {
+		clock.cond.Wait()
+	}
+}
+
+type fakeTimer struct {
+	clock    *FakeClock
+	c        chan time.Time
+	deadline time.Time
+}
+
+func (timer *fakeTimer) C() <-chan time.Time {
+	return timer.c
+}
+
+func (timer *fakeTimer) Reset(d time.Duration) bool {
+	timer.clock.mutex.Lock()
+	defer timer.clock.mutex.Unlock()
+
+	active := timer.clock.timers[timer]
+	timer.deadline = timer.clock.now.Add(d)
+	timer.clock.timers[timer] = true
+	if d <= 0 {
+		timer.clock.set(timer.clock.now)
+	}
+	timer.clock.cond.Broadcast()
+	return active
+}
+
+func (timer *fakeTimer) Stop() bool {
+	timer.clock.mutex.Lock()
+	defer timer.clock.mutex.Unlock()
+
+	active := timer.clock.timers[timer]
+	delete(timer.clock.timers, timer)
+	timer.clock.cond.Broadcast()
+	return active
+}
@@ -0,0 +1,63 @@
+package chord
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoffRunsOnFakeClock(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	network := NewChordNetwork(&ContactInfo{Address: "self", Id: NewNodeIDFromHash("self")})
+	network.SetClock(clock)
+	network.lastDirtyTime = clock.Now()
+
+	intervals := make(chan time.Duration, 1)
+	tf := StartTickingFunctionWithClock(clock, func() int {
+		interval := network.backoff(stabilizationIntervalStart, stabilizationIntervalEnd)
+		intervals <- interval
+		return int(interval)
+	})
+	defer tf.Stop()
+
+	start := time.Now()
+	rounds := 0
+	next := time.Second
+	for clock.Now().Sub(time.Unix(0, 0)) < 30*time.Minute {
+		clock.BlockUntil(1)
+		clock.Advance(next)
+		next = <-intervals
+		rounds++
+	}
+
+	if next != stabilizationIntervalEnd {
+		t.Errorf("interval is %v after half an hour without changes, expected %v", next, stabilizationIntervalEnd)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("%d rounds over half an hour of fake time took %v", rounds, elapsed)
+	}
+}
+
+func TestNudgeDuringRoundRunsAgain(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	started := make(chan bool)
+	release := make(chan bool)
+	tf := StartTickingFunctionWithClock(clock, func() int {
+		started <- true
+		<-release
+		return int(time.Hour)
+	})
+	defer tf.Stop()
+
+	tf.Nudge()
+	<-started
+	// The round may already have read the state the nudge is about
+	tf.Nudge()
+	release <- true
+
+	select {
+	case <-started:
+		release <- true
+	case <-time.After(time.Second):
+		t.Fatal("a nudge during a round was dropped")
+	}
+}
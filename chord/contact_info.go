@@ -1,7 +1,20 @@
 package chord
 
+import "sort"
+
 type ContactInfo struct {
 	Address string `json:"address"`
 	Id      NodeID `json:"id"`
 	Payload []byte `json:"payload"`
-}
\ No newline at end of file
+	// Version and Capabilities are what the node advertises about the
+	// protocol it speaks.
+	Version      uint32     `json:"version"`
+	Capabilities Capability `json:"capabilities"`
+}
+// sortContacts orders contacts by id, so that what is done for each of them
+// happens in the same order every time.
+func sortContacts(contacts []*ContactInfo) {
+	sort.Slice(contacts, func(i, j int) bool {
+		return contacts[i].Id.Less(contacts[j].Id)
+	})
+}
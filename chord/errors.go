@@ -0,0 +1,144 @@
+package chord
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lukaspj/go-chord/api"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorKind tells callers what went wrong without parsing messages. Each
+// kind travels as its own gRPC status code.
+type ErrorKind int
+
+const (
+	Internal ErrorKind = iota
+	InvalidArgument
+	NotFound
+	FailedPrecondition
+	Unavailable
+	Unsupported
+)
+
+var errorCodes = map[ErrorKind]codes.Code{
+	Internal:           codes.Internal,
+	InvalidArgument:    codes.InvalidArgument,
+	NotFound:           codes.NotFound,
+	FailedPrecondition: codes.FailedPrecondition,
+	Unavailable:        codes.Unavailable,
+	Unsupported:        codes.Unimplemented,
+}
+
+func (kind ErrorKind) String() string {
+	switch kind {
+	case InvalidArgument:
+		return "invalid argument"
+	case NotFound:
+		return "not found"
+	case FailedPrecondition:
+		return "failed precondition"
+	case Unavailable:
+		return "unavailable"
+	case Unsupported:
+		return "unsupported"
+	default:
+		return "internal"
+	}
+}
+
+func errorKindFromCode(code codes.Code) ErrorKind {
+	switch code {
+	case codes.DeadlineExceeded, codes.Canceled:
+		return Unavailable
+	}
+	for kind, c := range errorCodes {
+		if c == code {
+			return kind
+		}
+	}
+	return Internal
+}
+
+// Error is the error returned by the protocol, locally and over the wire.
+// Argument names the offending argument of an InvalidArgument error, Node
+// is the node that could not be reached for an Unavailable error.
+type Error struct {
+	Kind     ErrorKind
+	Message  string
+	Argument string
+	Node     *ContactInfo
+}
+
+func (e *Error) Error() string {
+	if e.Node != nil {
+		return fmt.Sprintf("%s: %s (%s)", e.Kind, e.Message, e.Node.Address)
+	}
+	return fmt.Sprintf("%s: %s", e.Kind, e.Message)
+}
+
+// GRPCStatus lets gRPC send the error with its code and details.
+func (e *Error) GRPCStatus() *status.Status {
+	s := status.New(errorCodes[e.Kind], e.Message)
+	info := &api.ErrorInfo{Argument: e.Argument}
+	if e.Node != nil {
+		info.Node = ContactInfoToAPI(e.Node)
+	}
+	if detailed, err := s.WithDetails(info); err == nil {
+		return detailed
+	}
+	return s
+}
+
+// ErrorKindOf returns the kind of err, Internal for errors that are not
+// from this package.
+func ErrorKindOf(err error) ErrorKind {
+	if e, ok := err.(*Error); ok {
+		return e.Kind
+	}
+	if s, ok := status.FromError(err); ok {
+		return errorKindFromCode(s.Code())
+	}
+	return Internal
+}
+
+func invalidArgument(argument, format string, args ...interface{}) *Error {
+	return &Error{Kind: InvalidArgument, Argument: argument, Message: fmt.Sprintf(format, args...)}
+}
+
+func newError(kind ErrorKind, format string, args ...interface{}) *Error {
+	return &Error{Kind: kind, Message: fmt.Sprintf(format, args...)}
+}
+
+// rpcError turns the error of a call to contact into an *Error, keeping
+// the kind and details the remote side sent. Failures that never reached
+// the remote side make contact unavailable.
+func rpcError(contact *ContactInfo, err error) error {
+	if err == nil {
+		return nil
+	}
+	if e, ok := err.(*Error); ok {
+		return e
+	}
+	if err == context.DeadlineExceeded || err == context.Canceled {
+		return &Error{Kind: Unavailable, Message: err.Error(), Node: contact}
+	}
+
+	s, ok := status.FromError(err)
+	if !ok {
+		return &Error{Kind: Unavailable, Message: err.Error(), Node: contact}
+	}
+
+	e := &Error{Kind: errorKindFromCode(s.Code()), Message: s.Message()}
+	for _, detail := range s.Details() {
+		if info, ok := detail.(*api.ErrorInfo); ok {
+			e.Argument = info.Argument
+			e.Node = NewContactInfoFromAPI(info.Node)
+		}
+	}
+	if e.Kind == Unavailable && e.Node == nil {
+		e.Node = contact
+	}
+	return e
+}
@@ -0,0 +1,109 @@
+package chord
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorsTravelAsCodes(t *testing.T) {
+	node := &ContactInfo{Address: "node", Id: NewNodeIDFromHash("node")}
+	contact := &ContactInfo{Address: "contact", Id: NewNodeIDFromHash("contact")}
+
+	tests := []struct {
+		err  *Error
+		code codes.Code
+	}{
+		{&Error{Kind: Internal, Message: "internal"}, codes.Internal},
+		{&Error{Kind: InvalidArgument, Message: "bad", Argument: "key"}, codes.InvalidArgument},
+		{&Error{Kind: NotFound, Message: "missing"}, codes.NotFound},
+		{&Error{Kind: FailedPrecondition, Message: "not yet"}, codes.FailedPrecondition},
+		{&Error{Kind: Unavailable, Message: "down", Node: node}, codes.Unavailable},
+		{&Error{Kind: Unsupported, Message: "too old"}, codes.Unimplemented},
+	}
+	for _, test := range tests {
+		sent := test.err.GRPCStatus()
+		if sent.Code() != test.code {
+			t.Errorf("%v is sent as %v, expected %v", test.err.Kind, sent.Code(), test.code)
+		}
+
+		received, ok := rpcError(contact, sent.Err()).(*Error)
+		if !ok {
+			t.Fatalf("%v is not received as an *Error", test.err.Kind)
+		}
+		if received.Kind != test.err.Kind || received.Message != test.err.Message || received.Argument != test.err.Argument {
+			t.Errorf("%+v is received as %+v", test.err, received)
+		}
+		if test.err.Node != nil && (received.Node == nil || !received.Node.Id.Equals(test.err.Node.Id)) {
+			t.Errorf("%v names %v, expected %s", test.err.Kind, received.Node, test.err.Node.Address)
+		}
+	}
+}
+
+func TestErrorsFromElsewhere(t *testing.T) {
+	contact := &ContactInfo{Address: "contact", Id: NewNodeIDFromHash("contact")}
+
+	tests := []struct {
+		name string
+		err  error
+		kind ErrorKind
+	}{
+		{"deadline", status.Error(codes.DeadlineExceeded, "too slow"), Unavailable},
+		{"canceled", status.Error(codes.Canceled, "gave up"), Unavailable},
+		{"context", context.DeadlineExceeded, Unavailable},
+		{"unknown code", status.Error(codes.DataLoss, "lost"), Internal},
+		{"unavailable", status.Error(codes.Unavailable, "down"), Unavailable},
+		{"plain", errors.New("connection refused"), Unavailable},
+	}
+	for _, test := range tests {
+		err := rpcError(contact, test.err)
+		if kind := ErrorKindOf(err); kind != test.kind {
+			t.Errorf("%s error is %v, expected %v", test.name, kind, test.kind)
+		}
+		if e := err.(*Error); e.Kind == Unavailable && (e.Node == nil || e.Node.Address != contact.Address) {
+			t.Errorf("%s error names %v as unavailable, expected %s", test.name, e.Node, contact.Address)
+		}
+	}
+	if err := rpcError(contact, nil); err != nil {
+		t.Errorf("no error became %v", err)
+	}
+}
+
+func TestEmptyReplies(t *testing.T) {
+	peers := wiredRing(t, 1)
+	defer stopAll(peers)
+	peer := peers[0]
+	client := NewChordNetwork(&ContactInfo{Id: NewEmptyNodeID()})
+
+	other := &ContactInfo{Address: "other", Id: NewNodeIDFromHash("other")}
+	for _, pred := range []*ContactInfo{nil, other} {
+		peer.network.setPredecessor(pred)
+		found, err := client.Predecessor(peer.GetInfo())
+		switch {
+		case err != nil:
+			t.Errorf("predecessor %v could not be fetched: %v", pred, err)
+		case pred == nil && found != nil:
+			t.Errorf("peer without a predecessor answered with %s", found.Address)
+		case pred != nil && (found == nil || !found.Id.Equals(pred.Id)):
+			t.Errorf("predecessor is %v, expected %s", found, pred.Address)
+		}
+
+		neighbours := NewNeighboursFromAPI(NeighboursToAPI(Neighbours{Node: peer.GetInfo(), Predecessor: pred}))
+		if (neighbours.Predecessor == nil) != (pred == nil) {
+			t.Errorf("neighbours with predecessor %v came back with %v", pred, neighbours.Predecessor)
+		}
+	}
+
+	if item, err := client.Fetch(peer.GetInfo(), "missing"); err != nil || item != nil {
+		t.Errorf("fetch of a missing key returned %+v, %v", item, err)
+	}
+	if err := client.Store(peer.GetInfo(), &Item{Key: "present", Value: []byte("value")}); err != nil {
+		t.Fatal(err)
+	}
+	if item, err := client.Fetch(peer.GetInfo(), "present"); err != nil || item == nil || string(item.Value) != "value" {
+		t.Errorf("fetch of a stored key returned %+v, %v", item, err)
+	}
+}
@@ -0,0 +1,166 @@
+package chord
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// NodeState is what the failure detector believes about a node. A node that
+// misses a direct and all indirect probes becomes Suspect, and only turns
+// Dead, which is when routing state is changed, if it stays that way.
+type NodeState int
+
+const (
+	Alive NodeState = iota
+	Suspect
+	Dead
+)
+
+func (state NodeState) String() string {
+	switch state {
+	case Alive:
+		return "alive"
+	case Suspect:
+		return "suspect"
+	case Dead:
+		return "dead"
+	}
+	return fmt.Sprintf("NodeState(%d)", int(state))
+}
+
+type FailureDetectorConfig struct {
+	// IndirectProbes is how many other successors are asked to ping a node
+	// that did not answer a direct ping.
+	IndirectProbes int `json:"indirect_probes"`
+	// SuspectTimeout is how long a node may stay suspect before it is
+	// declared dead.
+	SuspectTimeout time.Duration `json:"suspect_timeout"`
+}
+
+var DefaultFailureDetectorConfig = FailureDetectorConfig{
+	IndirectProbes: 2,
+	SuspectTimeout: 30 * time.Second,
+}
+
+type nodeStatus struct {
+	state NodeState
+	since time.Time
+}
+
+// failureDetector implements SWIM-style probing with a suspicion period.
+type failureDetector struct {
+	mutex    sync.Mutex
+	config   FailureDetectorConfig
+	clock    Clock
+	statuses map[string]*nodeStatus
+}
+
+func newFailureDetector(config FailureDetectorConfig) *failureDetector {
+	return &failureDetector{
+		config:   config,
+		clock:    realClock{},
+		statuses: make(map[string]*nodeStatus),
+	}
+}
+
+func (detector *failureDetector) State(id NodeID) NodeState {
+	detector.mutex.Lock()
+	defer detector.mutex.Unlock()
+
+	if status, ok := detector.statuses[id.String()]; ok {
+		return status.state
+	}
+	return Alive
+}
+
+func (detector *failureDetector) Alive(id NodeID) {
+	detector.mutex.Lock()
+	defer detector.mutex.Unlock()
+
+	if status, ok := detector.statuses[id.String()]; ok && status.state != Alive {
+		logger.Info("%s is alive again", id.String())
+	}
+	delete(detector.statuses, id.String())
+}
+
+// Failed records a failed probe and returns the resulting state.
+func (detector *failureDetector) Failed(id NodeID) NodeState {
+	detector.mutex.Lock()
+	defer detector.mutex.Unlock()
+
+	status, ok := detector.statuses[id.String()]
+	if !ok {
+		logger.Warn("%s is suspected to have failed", id.String())
+		status = &nodeStatus{state: Suspect, since: detector.clock.Now()}
+		detector.statuses[id.String()] = status
+	}
+	if status.state == Suspect && detector.clock.Now().Sub(status.since) >= detector.config.SuspectTimeout {
+		logger.Warn("%s has been suspect for %v, declaring it dead", id.String(), detector.config.SuspectTimeout)
+		status.state = Dead
+		status.since = detector.clock.Now()
+	}
+	return status.state
+}
+
+// Probe pings target directly and, failing that, through some of our other
+// successors, and returns what the failure detector now believes about it.
+// The contact info is the one the target answered with when it is alive.
+func (network *chordNetwork) Probe(target *ContactInfo) (*ContactInfo, NodeState) {
+	if info, err := network.PingNode(target); err == nil {
+		network.detector.Alive(target.Id)
+		network.alive(info)
+		return info, Alive
+	}
+
+	probes := 0
+	for _, helper := range network.successors.List() {
+		if probes >= network.detector.config.IndirectProbes {
+			break
+		}
+		if helper == nil || helper.Id.Equals(target.Id) || helper.Id.Equals(network.self().Id) {
+			continue
+		}
+		if !network.supports(helper, CapProbe) {
+			continue
+		}
+		probes++
+		if err := network.ProbeNode(helper, target); err == nil {
+			logger.Info("%s did not answer us, but answered %s", target.Address, helper.Address)
+			network.detector.Alive(target.Id)
+			return target, Alive
+		}
+	}
+
+	return target, network.detector.Failed(target.Id)
+}
+
+// moved tells whether info no longer answers under its id, the node on its
+// address has moved or is a different one.
+func (network *chordNetwork) moved(info *ContactInfo) bool {
+	answer, err := network.PingNode(info)
+	return err != nil || !answer.Id.Equals(info.Id)
+}
+
+func (network *chordNetwork) ProbeNode(helper *ContactInfo, target *ContactInfo) (err error) {
+	err = network.Call(helper, func(client ChordClient) error {
+		err = client.ProbeNode(context.Background(), target)
+		return err
+	})
+	return
+}
+
+// ProbeNode pings target on behalf of a node that could not reach it.
+func (peer *Peer) ProbeNode(ctx context.Context, target *ContactInfo) (err error) {
+	logger.Debug("ProbeNode: %s", target.Address)
+	_, err = peer.network.PingNode(target)
+
+	return
+}
+
+// NodeState returns what the failure detector believes about the node with
+// the given id. Nodes it has never failed to reach are Alive.
+func (peer *Peer) NodeState(id NodeID) NodeState {
+	return peer.network.detector.State(id)
+}
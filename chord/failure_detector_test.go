@@ -0,0 +1,65 @@
+package chord
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFailureDetectorStates(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	detector := newFailureDetector(DefaultFailureDetectorConfig)
+	detector.clock = clock
+	id := NewNodeIDFromHash("node")
+
+	if state := detector.State(id); state != Alive {
+		t.Fatalf("unknown node is %v, expected it to be alive", state)
+	}
+	if state := detector.Failed(id); state != Suspect {
+		t.Fatalf("node is %v after a failed probe, expected it to be suspect", state)
+	}
+	clock.Advance(DefaultFailureDetectorConfig.SuspectTimeout - time.Second)
+	if state := detector.Failed(id); state != Suspect {
+		t.Fatalf("node is %v before the suspect timeout, expected it to be suspect", state)
+	}
+	clock.Advance(time.Second)
+	if state := detector.Failed(id); state != Dead {
+		t.Fatalf("node is %v after the suspect timeout, expected it to be dead", state)
+	}
+	detector.Alive(id)
+	if state := detector.State(id); state != Alive {
+		t.Errorf("node is %v after answering, expected it to be alive", state)
+	}
+}
+
+func TestProbe(t *testing.T) {
+	target := listeningPeer(t)
+	defer target.Stop()
+	helper := listeningPeer(t)
+	defer helper.Stop()
+
+	clock := NewFakeClock(time.Unix(0, 0))
+	faults := NewFaults(1)
+	p := NewPeer(&ContactInfo{Address: "prober", Id: NewNodeIDFromHash("prober")}, 0)
+	prober := &p
+	prober.Clock = clock
+	prober.Faults = faults
+	prober.Reset()
+	prober.network.successors.SetSuccessor(0, target.GetInfo())
+	prober.network.successors.SetSuccessor(1, helper.GetInfo())
+
+	// We cannot reach the target, but the helper can
+	faults.Block("prober", target.GetInfo().Address)
+	if _, state := prober.network.Probe(target.GetInfo()); state != Alive {
+		t.Fatalf("target that answers the helper is %v, expected it to be alive", state)
+	}
+
+	// Once it has crashed nobody can
+	target.Stop()
+	if _, state := prober.network.Probe(target.GetInfo()); state != Suspect {
+		t.Fatalf("crashed target is %v, expected it to be suspect", state)
+	}
+	clock.Advance(DefaultFailureDetectorConfig.SuspectTimeout)
+	if _, state := prober.network.Probe(target.GetInfo()); state != Dead {
+		t.Fatalf("target is %v after the suspect timeout, expected it to be dead", state)
+	}
+}
@@ -0,0 +1,221 @@
+package chord
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Faults injects failures into the RPCs of the peers it is installed on,
+// to test the protocol under lossy and partitioned conditions. Requests are
+// delayed, dropped and blocked on the calling side, injected errors and
+// lost replies happen on the answering side. All settings can be changed
+// while the peers run, and one Faults can be shared by a whole ring.
+type Faults struct {
+	mutex     sync.Mutex
+	random    *rand.Rand
+	latency   time.Duration
+	dropRate  float64
+	errorRate float64
+	errorCode codes.Code
+	blocked   map[[2]string]bool
+}
+
+func NewFaults(seed int64) *Faults {
+	return &Faults{
+		random:  rand.New(rand.NewSource(seed)),
+		blocked: make(map[[2]string]bool),
+	}
+}
+
+// SetLatency delays every request by d before it is sent.
+func (faults *Faults) SetLatency(d time.Duration) {
+	faults.mutex.Lock()
+	defer faults.mutex.Unlock()
+	faults.latency = d
+}
+
+// SetDropRate makes every request, and every reply, get lost with
+// probability p. The caller sees Unavailable either way.
+func (faults *Faults) SetDropRate(p float64) {
+	faults.mutex.Lock()
+	defer faults.mutex.Unlock()
+	faults.dropRate = p
+}
+
+// SetErrorRate makes the answering side fail a request with code, without
+// handling it, with probability p.
+func (faults *Faults) SetErrorRate(p float64, code codes.Code) {
+	faults.mutex.Lock()
+	defer faults.mutex.Unlock()
+	faults.errorRate = p
+	faults.errorCode = code
+}
+
+// Block stops all traffic between the nodes at addresses a and b, in both
+// directions.
+func (faults *Faults) Block(a, b string) {
+	faults.mutex.Lock()
+	defer faults.mutex.Unlock()
+	faults.blocked[[2]string{a, b}] = true
+	faults.blocked[[2]string{b, a}] = true
+}
+
+func (faults *Faults) Unblock(a, b string) {
+	faults.mutex.Lock()
+	defer faults.mutex.Unlock()
+	delete(faults.blocked, [2]string{a, b})
+	delete(faults.blocked, [2]string{b, a})
+}
+
+// Heal turns every fault off.
+func (faults *Faults) Heal() {
+	faults.mutex.Lock()
+	defer faults.mutex.Unlock()
+	faults.latency = 0
+	faults.dropRate = 0
+	faults.errorRate = 0
+	faults.blocked = make(map[[2]string]bool)
+}
+
+func (faults *Faults) chance(p float64) bool {
+	return p > 0 && faults.random.Float64() < p
+}
+
+// send decides what happens to a request from one address to another
+// before it is sent, and how long it is held back.
+func (faults *Faults) send(from, to string) (time.Duration, error) {
+	faults.mutex.Lock()
+	defer faults.mutex.Unlock()
+
+	if faults.blocked[[2]string{from, to}] {
+		return 0, status.Errorf(codes.Unavailable, "traffic from %s to %s is blocked", from, to)
+	}
+	if faults.chance(faults.dropRate) {
+		return 0, status.Errorf(codes.Unavailable, "request from %s to %s was dropped", from, to)
+	}
+	return faults.latency, nil
+}
+
+// receive decides what happens to a request when it arrives.
+func (faults *Faults) receive() error {
+	faults.mutex.Lock()
+	defer faults.mutex.Unlock()
+
+	if faults.chance(faults.errorRate) {
+		return status.Error(faults.errorCode, "injected fault")
+	}
+	return nil
+}
+
+// reply decides whether the reply to a handled request is lost.
+func (faults *Faults) reply() error {
+	faults.mutex.Lock()
+	defer faults.mutex.Unlock()
+
+	if faults.chance(faults.dropRate) {
+		return status.Error(codes.Unavailable, "reply was dropped")
+	}
+	return nil
+}
+
+func wait(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// UnaryClientInterceptor applies the faults to calls from the node at
+// address from to the node at address to.
+func (faults *Faults) UnaryClientInterceptor(from, to string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		latency, err := faults.send(from, to)
+		if err == nil {
+			err = wait(ctx, latency)
+		}
+		if err != nil {
+			return err
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+func (faults *Faults) StreamClientInterceptor(from, to string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		latency, err := faults.send(from, to)
+		if err == nil {
+			err = wait(ctx, latency)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+}
+
+func (faults *Faults) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := faults.receive(); err != nil {
+			return nil, err
+		}
+		resp, err := handler(ctx, req)
+		if err == nil {
+			err = faults.reply()
+		}
+		return resp, err
+	}
+}
+
+func (faults *Faults) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := faults.receive(); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func chainUnaryClient(outer, inner grpc.UnaryClientInterceptor) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return outer(ctx, method, req, reply, cc, func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return inner(ctx, method, req, reply, cc, invoker, opts...)
+		}, opts...)
+	}
+}
+
+func chainStreamClient(outer, inner grpc.StreamClientInterceptor) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return outer(ctx, desc, cc, method, func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return inner(ctx, desc, cc, method, streamer, opts...)
+		}, opts...)
+	}
+}
+
+func chainUnaryServer(outer, inner grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return outer(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return inner(ctx, req, info, handler)
+		})
+	}
+}
+
+func chainStreamServer(outer, inner grpc.StreamServerInterceptor) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return outer(srv, ss, info, func(srv interface{}, ss grpc.ServerStream) error {
+			return inner(srv, ss, info, handler)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package chord
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+func TestFaultsReachTheCaller(t *testing.T) {
+	faults := NewFaults(1)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := l.Addr().String()
+	p := NewPeer(&ContactInfo{Address: address, Id: NewNodeIDFromHash(address)}, 0)
+	target := &p
+	target.Faults = faults
+	target.Reset()
+	target.Serve(l)
+	defer target.Stop()
+
+	client := NewChordNetwork(&ContactInfo{Address: "client", Id: NewEmptyNodeID()})
+	client.transport = grpcTransport{faults: faults, from: "client"}
+
+	tests := []struct {
+		name   string
+		inject func()
+		kind   ErrorKind
+	}{
+		{"dropped", func() { faults.SetDropRate(1) }, Unavailable},
+		{"blocked", func() { faults.Block("client", address) }, Unavailable},
+		{"failed", func() { faults.SetErrorRate(1, codes.NotFound) }, NotFound},
+	}
+	for _, test := range tests {
+		test.inject()
+		_, err := client.Ping(address)
+		if ErrorKindOf(err) != test.kind {
+			t.Errorf("%s request returned %v, expected it to be %v", test.name, err, test.kind)
+		}
+		faults.Heal()
+		if _, err = client.Ping(address); err != nil {
+			t.Errorf("request after the %s one was healed failed: %v", test.name, err)
+		}
+	}
+
+	latency := 100 * time.Millisecond
+	faults.SetLatency(latency)
+	start := time.Now()
+	if _, err = client.Ping(address); err != nil {
+		t.Fatalf("delayed request failed: %v", err)
+	}
+	if took := time.Since(start); took < latency {
+		t.Errorf("request with %v of latency took %v", latency, took)
+	}
+}
@@ -1,21 +1,89 @@
 package chord
 
-const fingerCount = 10
+import (
+	"crypto/sha256"
+	"sync"
+)
 
+// fingerCount is one finger per bit of the ids NewNodeIDFromHash makes, so
+// the fingers span the whole ring and lookups take O(log N) hops.
+const fingerCount = sha256.Size * 8
+
+// fingerTable is safe for concurrent use, as fix-fingers changes it while
+// lookups read it.
 type fingerTable struct {
-	fingers [fingerCount]*ContactInfo
-	next    int
+	mutex      sync.RWMutex
+	fingers    [fingerCount]*ContactInfo
+	candidates [fingerCount][]*ContactInfo
+	next       int
 }
 
 func (table *fingerTable) GetFinger(index int) *ContactInfo {
+	table.mutex.RLock()
+	defer table.mutex.RUnlock()
 	return table.fingers[index]
 }
 
+// SetFinger stores info as the finger at index and returns whether it is a
+// different node than before. A newer contact info of the same node replaces
+// the one held, but returns false.
 func (table *fingerTable) SetFinger(index int, info *ContactInfo) bool {
+	table.mutex.Lock()
+	defer table.mutex.Unlock()
 	if table.fingers[index] == nil || !info.Id.Equals(table.fingers[index].Id) {
 		logger.Debug("Setting finger %d to: %s", index, info.Id.String())
 		table.fingers[index] = info
 		return true
 	}
+	table.fingers[index] = info
 	return false
 }
+
+// Fingers returns a copy of the fingers.
+func (table *fingerTable) Fingers() []*ContactInfo {
+	table.mutex.RLock()
+	defer table.mutex.RUnlock()
+	return append([]*ContactInfo(nil), table.fingers[:]...)
+}
+
+// Clear forgets every finger, as when our id has changed.
+func (table *fingerTable) Clear() {
+	table.mutex.Lock()
+	defer table.mutex.Unlock()
+	table.fingers = [fingerCount]*ContactInfo{}
+	table.candidates = [fingerCount][]*ContactInfo{}
+	table.next = 0
+}
+
+// Advance moves on to the next finger to fix and returns its index.
+func (table *fingerTable) Advance() int {
+	table.mutex.Lock()
+	defer table.mutex.Unlock()
+	table.next++
+	if table.next >= fingerCount {
+		table.next = 0
+	}
+	return table.next
+}
+
+// SetNext makes index the last finger fixed, so the next round starts after
+// it.
+func (table *fingerTable) SetNext(index int) {
+	table.mutex.Lock()
+	defer table.mutex.Unlock()
+	table.next = index
+}
+
+// SetCandidates remembers the nodes that were considered for a finger when
+// picking by proximity.
+func (table *fingerTable) SetCandidates(index int, candidates []*ContactInfo) {
+	table.mutex.Lock()
+	defer table.mutex.Unlock()
+	table.candidates[index] = candidates
+}
+
+func (table *fingerTable) GetCandidates(index int) []*ContactInfo {
+	table.mutex.RLock()
+	defer table.mutex.RUnlock()
+	return table.candidates[index]
+}
@@ -3,6 +3,7 @@ package chord
 import (
 	"google.golang.org/grpc"
 	"context"
+	"io"
 	"github.com/lukaspj/go-chord/api"
 )
 
@@ -12,27 +13,37 @@ type ChordClient struct {
 
 func (client *ChordClient) Ping(ctx context.Context, opts ...grpc.CallOption) (*ContactInfo, error) {
 	ci, err := client.api.Ping(context.Background(), &api.Void{}, opts...)
-	return NewContactInfoFromAPI(ci), err
+	return contactFromReply(ci, err)
 }
 
 func (client *ChordClient) FindSuccessor(ctx context.Context, in NodeID, opts ...grpc.CallOption) (*ContactInfo, error) {
 	ci, err := client.api.FindSuccessor(ctx, &api.Id{Hash: in.String()}, opts...)
-	return NewContactInfoFromAPI(ci), err
+	return contactFromReply(ci, err)
 }
 
 func (client *ChordClient) ClosestPrecedingNode(ctx context.Context, in NodeID, opts ...grpc.CallOption) (*ContactInfo, error) {
 	ci, err := client.api.ClosestPrecedingNode(ctx, &api.Id{Hash: in.String()}, opts...)
-	return NewContactInfoFromAPI(ci), err
+	return contactFromReply(ci, err)
 }
 
-func (client *ChordClient) Predecessor(ctx context.Context, opts ...grpc.CallOption) (*ContactInfo, error) {
+// LegacyPredecessor asks a peer from before CurrentPredecessor, which
+// answers with an empty ContactInfo when it has no predecessor.
+func (client *ChordClient) LegacyPredecessor(ctx context.Context, opts ...grpc.CallOption) (*ContactInfo, error) {
 	ci, err := client.api.Predecessor(ctx, &api.Void{}, opts...)
 	return NewContactInfoFromAPI(ci), err
 }
 
+func (client *ChordClient) Predecessor(ctx context.Context, opts ...grpc.CallOption) (*ContactInfo, error) {
+	reply, err := client.api.CurrentPredecessor(ctx, &api.Void{}, opts...)
+	if err != nil || !reply.HasPredecessor {
+		return nil, err
+	}
+	return contactFromReply(reply.Predecessor, nil)
+}
+
 func (client *ChordClient) Successor(ctx context.Context, opts ...grpc.CallOption) (*ContactInfo, error) {
 	ci, err := client.api.Successor(ctx, &api.Void{}, opts...)
-	return NewContactInfoFromAPI(ci), err
+	return contactFromReply(ci, err)
 }
 
 func (client *ChordClient) Notify(ctx context.Context, in *ContactInfo, opts ...grpc.CallOption) (error) {
@@ -40,6 +51,174 @@ func (client *ChordClient) Notify(ctx context.Context, in *ContactInfo, opts ...
 	return err
 }
 
+func (client *ChordClient) SuccessorList(ctx context.Context, opts ...grpc.CallOption) ([]*ContactInfo, error) {
+	list, err := client.api.SuccessorList(ctx, &api.Void{}, opts...)
+	return NewContactInfoListFromAPI(list), err
+}
+
+func (client *ChordClient) Store(ctx context.Context, in *Item, opts ...grpc.CallOption) (error) {
+	_, err := client.api.Store(ctx, ItemToAPI(in), opts...)
+	return err
+}
+
+func (client *ChordClient) Fetch(ctx context.Context, key string, opts ...grpc.CallOption) (*Item, error) {
+	reply, err := client.api.Fetch(ctx, &api.Key{Key: key}, opts...)
+	if err != nil || !reply.Found {
+		return nil, err
+	}
+	if item := NewItemFromAPI(reply.Item); item != nil {
+		return item, nil
+	}
+	return nil, newError(Internal, "Fetch reply is found but has no item")
+}
+
+func (client *ChordClient) StoreHint(ctx context.Context, target *ContactInfo, item *Item, opts ...grpc.CallOption) (error) {
+	_, err := client.api.StoreHint(ctx, &api.Hint{Target: ContactInfoToAPI(target), Item: ItemToAPI(item)}, opts...)
+	return err
+}
+
+func (client *ChordClient) Load(ctx context.Context, opts ...grpc.CallOption) (*LoadReport, error) {
+	report, err := client.api.Load(ctx, &api.Void{}, opts...)
+	return NewLoadReportFromAPI(report), err
+}
+
+// FindSuccessors resolves a batch of ids, collecting the streamed results in
+// the order the ids were given.
+func (client *ChordClient) FindSuccessors(ctx context.Context, in []NodeID, opts ...grpc.CallOption) ([]*ContactInfo, error) {
+	list := &api.IdList{}
+	for i := range in {
+		list.Ids = append(list.Ids, NodeIDToAPI(&in[i]))
+	}
+
+	stream, err := client.api.FindSuccessors(ctx, list, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]*ContactInfo)
+	for {
+		lookup, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if id := NewNodeIDFromAPI(lookup.GetId()); id != nil && lookup.Found {
+			found[id.String()] = NewContactInfoFromAPI(lookup.Successor)
+		}
+	}
+
+	res := make([]*ContactInfo, len(in))
+	for i, id := range in {
+		res[i] = found[id.String()]
+	}
+	return res, nil
+}
+
+func (client *ChordClient) ProbeNode(ctx context.Context, target *ContactInfo, opts ...grpc.CallOption) (error) {
+	_, err := client.api.ProbeNode(ctx, ContactInfoToAPI(target), opts...)
+	return err
+}
+
+func (client *ChordClient) Gossip(ctx context.Context, in []Heartbeat, opts ...grpc.CallOption) ([]Heartbeat, error) {
+	list, err := client.api.Gossip(ctx, HeartbeatsToAPI(in), opts...)
+	return NewHeartbeatsFromAPI(list), err
+}
+
+// Watch calls fn with each update the node streams, until fn returns false.
+// The node only ends the stream when it stops, so that is an error.
+func (client *ChordClient) Watch(ctx context.Context, fn func(Neighbours) bool, opts ...grpc.CallOption) error {
+	stream, err := client.api.Watch(ctx, &api.Void{}, opts...)
+	if err != nil {
+		return err
+	}
+
+	for {
+		update, err := stream.Recv()
+		if err == io.EOF {
+			return newError(Unavailable, "watch ended by the node")
+		}
+		if err != nil {
+			return err
+		}
+		if !fn(NewNeighboursFromAPI(update)) {
+			return nil
+		}
+	}
+}
+
+// Neighbours asks for the node's predecessor and successor list, notifying
+// it of notify first unless that is nil.
+func (client *ChordClient) Neighbours(ctx context.Context, notify *ContactInfo, opts ...grpc.CallOption) (Neighbours, error) {
+	req := &api.NeighboursRequest{}
+	if notify != nil {
+		req.Notify = ContactInfoToAPI(notify)
+	}
+	update, err := client.api.Neighbours(ctx, req, opts...)
+	if err != nil {
+		return Neighbours{}, err
+	}
+	neighbours := NewNeighboursFromAPI(update)
+	if neighbours.Node == nil {
+		return Neighbours{}, newError(Internal, "reply has no node")
+	}
+	return neighbours, nil
+}
+
+func (client *ChordClient) Route(ctx context.Context, msg *Message, opts ...grpc.CallOption) (*Reply, error) {
+	reply, err := client.api.Route(ctx, MessageToAPI(msg), opts...)
+	return NewReplyFromAPI(reply), err
+}
+
+func (client *ChordClient) Broadcast(ctx context.Context, b *Broadcast, opts ...grpc.CallOption) (*BroadcastAck, error) {
+	ack, err := client.api.Broadcast(ctx, BroadcastToAPI(b), opts...)
+	return NewBroadcastAckFromAPI(ack), err
+}
+
+func (client *ChordClient) JoinTopic(ctx context.Context, topic string, child *ContactInfo, final bool, opts ...grpc.CallOption) error {
+	_, err := client.api.JoinTopic(ctx, &api.TopicJoin{Topic: topic, Child: ContactInfoToAPI(child), Final: final}, opts...)
+	return err
+}
+
+func (client *ChordClient) LeaveTopic(ctx context.Context, topic string, child *ContactInfo, opts ...grpc.CallOption) error {
+	_, err := client.api.LeaveTopic(ctx, &api.TopicJoin{Topic: topic, Child: ContactInfoToAPI(child)}, opts...)
+	return err
+}
+
+func (client *ChordClient) Publish(ctx context.Context, msg *TopicMessage, opts ...grpc.CallOption) error {
+	_, err := client.api.Publish(ctx, TopicMessageToAPI(msg), opts...)
+	return err
+}
+
+func (client *ChordClient) Multicast(ctx context.Context, msg *TopicMessage, opts ...grpc.CallOption) error {
+	_, err := client.api.Multicast(ctx, TopicMessageToAPI(msg), opts...)
+	return err
+}
+
+func (client *ChordClient) Aggregate(ctx context.Context, id string, limit NodeID, opts ...grpc.CallOption) (*Aggregate, error) {
+	reply, err := client.api.Aggregate(ctx, &api.AggregateRequest{Id: id, Limit: NodeIDToAPI(&limit)}, opts...)
+	return NewAggregateFromAPI(reply), err
+}
+
+func (client *ChordClient) FindNodes(ctx context.Context, id string, limit NodeID, query Query, opts ...grpc.CallOption) ([]*ContactInfo, error) {
+	list, err := client.api.FindNodes(ctx, &api.NodeQuery{Id: id, Limit: NodeIDToAPI(&limit), Attributes: query}, opts...)
+	return NewContactInfoListFromAPI(list), err
+}
+
+// contactFromReply returns the node a call answered with. Those calls
+// always answer with a node when they succeed, a reply without one is an
+// error rather than an absent node.
+func contactFromReply(ci *api.ContactInfo, err error) (*ContactInfo, error) {
+	if err != nil {
+		return nil, err
+	}
+	if info := NewContactInfoFromAPI(ci); info != nil {
+		return info, nil
+	}
+	return nil, newError(Internal, "reply has no node")
+}
+
 func NewChordClient(cc *grpc.ClientConn) ChordClient {
 	return ChordClient{
 		api: api.NewChordClient(cc),
@@ -9,11 +9,13 @@ func ContactInfoToAPI(ci *ContactInfo) *api.ContactInfo {
 		Address: ci.Address,
 		Id: NodeIDToAPI(&ci.Id),
 		Payload: ci.Payload,
+		Version: ci.Version,
+		Capabilities: uint64(ci.Capabilities),
 	}
 }
 
 func NewContactInfoFromAPI(info *api.ContactInfo) *ContactInfo {
-	if info.Id == nil {
+	if info == nil || info.Id == nil {
 		return nil
 	}
 
@@ -22,9 +24,53 @@ func NewContactInfoFromAPI(info *api.ContactInfo) *ContactInfo {
 		Address: info.Address,
 		Id: *nid,
 		Payload: info.Payload,
+		Version: info.Version,
+		Capabilities: Capability(info.Capabilities),
 	}
 }
 
+func LoadReportToAPI(report *LoadReport) *api.LoadReport {
+	return &api.LoadReport{
+		Id: NodeIDToAPI(&report.Id),
+		Arc: report.Arc,
+		RequestRate: report.RequestRate,
+		Keys: uint64(report.Keys),
+	}
+}
+
+func NewLoadReportFromAPI(report *api.LoadReport) *LoadReport {
+	if report == nil || report.Id == nil {
+		return nil
+	}
+
+	return &LoadReport{
+		Id: *NewNodeIDFromAPI(report.Id),
+		Arc: report.Arc,
+		RequestRate: report.RequestRate,
+		Keys: int(report.Keys),
+	}
+}
+
+func HeartbeatsToAPI(heartbeats []Heartbeat) *api.HeartbeatList {
+	ret := &api.HeartbeatList{}
+	for _, hb := range heartbeats {
+		ret.Heartbeats = append(ret.Heartbeats, &api.Heartbeat{
+			Info: ContactInfoToAPI(hb.Info),
+			Count: hb.Count,
+		})
+	}
+	return ret
+}
+
+func NewHeartbeatsFromAPI(list *api.HeartbeatList) (ret []Heartbeat) {
+	for _, hb := range list.GetHeartbeats() {
+		if info := NewContactInfoFromAPI(hb.Info); info != nil {
+			ret = append(ret, Heartbeat{Info: info, Count: hb.Count})
+		}
+	}
+	return
+}
+
 func NodeIDToAPI(node *NodeID) *api.NodeId {
 	return &api.NodeId{
 		Val: node.Val,
@@ -32,7 +78,7 @@ func NodeIDToAPI(node *NodeID) *api.NodeId {
 }
 
 func NewNodeIDFromAPI(id *api.NodeId) *NodeID {
-	if id.Val == nil {
+	if id == nil || id.Val == nil {
 		return nil
 	}
 
@@ -53,4 +99,219 @@ func NewNodeIDFromAPIId(id *api.Id) *NodeID {
 		ret = NewNodeIDFromString(id.Hash)
 	}
 	return &ret
-}
\ No newline at end of file
+}
+
+func ItemToAPI(item *Item) *api.Item {
+	return &api.Item{
+		Key: item.Key,
+		Value: item.Value,
+		Version: item.Version,
+	}
+}
+
+func NewItemFromAPI(item *api.Item) *Item {
+	if item == nil || item.Key == "" {
+		return nil
+	}
+
+	return &Item{
+		Key: item.Key,
+		Value: item.Value,
+		Version: item.Version,
+	}
+}
+
+func ContactInfoListToAPI(list []*ContactInfo) *api.ContactInfoList {
+	ret := &api.ContactInfoList{}
+	for _, ci := range list {
+		ret.Contacts = append(ret.Contacts, ContactInfoToAPI(ci))
+	}
+	return ret
+}
+
+func NewContactInfoListFromAPI(list *api.ContactInfoList) (ret []*ContactInfo) {
+	for _, ci := range list.GetContacts() {
+		if info := NewContactInfoFromAPI(ci); info != nil {
+			ret = append(ret, info)
+		}
+	}
+	return
+}
+
+func NeighboursToAPI(neighbours Neighbours) *api.NeighbourUpdate {
+	ret := &api.NeighbourUpdate{
+		Node: ContactInfoToAPI(neighbours.Node),
+		Successors: ContactInfoListToAPI(neighbours.Successors).Contacts,
+	}
+	if neighbours.Predecessor != nil {
+		ret.HasPredecessor = true
+		ret.Predecessor = ContactInfoToAPI(neighbours.Predecessor)
+	}
+	return ret
+}
+
+func NewNeighboursFromAPI(update *api.NeighbourUpdate) Neighbours {
+	neighbours := Neighbours{
+		Node: NewContactInfoFromAPI(update.Node),
+		Successors: NewContactInfoListFromAPI(&api.ContactInfoList{Contacts: update.Successors}),
+	}
+	if update.HasPredecessor {
+		neighbours.Predecessor = NewContactInfoFromAPI(update.Predecessor)
+	}
+	return neighbours
+}
+
+func MessageToAPI(msg *Message) *api.Message {
+	ret := &api.Message{
+		Key: NodeIDToAPI(&msg.Key),
+		Payload: msg.Payload,
+		Hops: uint32(msg.Hops),
+		Final: msg.Final,
+	}
+	if msg.Origin != nil {
+		ret.Origin = ContactInfoToAPI(msg.Origin)
+	}
+	return ret
+}
+
+func NewMessageFromAPI(msg *api.Message) *Message {
+	if msg == nil || msg.Key == nil {
+		return nil
+	}
+
+	return &Message{
+		Key: *NewNodeIDFromAPI(msg.Key),
+		Payload: msg.Payload,
+		Origin: NewContactInfoFromAPI(msg.Origin),
+		Hops: int(msg.Hops),
+		Final: msg.Final,
+	}
+}
+
+func ReplyToAPI(reply *Reply) *api.Reply {
+	return &api.Reply{
+		Payload: reply.Payload,
+		Node: ContactInfoToAPI(reply.Node),
+		Hops: uint32(reply.Hops),
+	}
+}
+
+func NewReplyFromAPI(reply *api.Reply) *Reply {
+	if reply == nil {
+		return nil
+	}
+
+	return &Reply{
+		Payload: reply.Payload,
+		Node: NewContactInfoFromAPI(reply.Node),
+		Hops: int(reply.Hops),
+	}
+}
+
+func BroadcastToAPI(b *Broadcast) *api.BroadcastMessage {
+	ret := &api.BroadcastMessage{
+		Id: b.Id,
+		Payload: b.Payload,
+		Limit: NodeIDToAPI(&b.Limit),
+		Ack: b.Ack,
+	}
+	if b.Origin != nil {
+		ret.Origin = ContactInfoToAPI(b.Origin)
+	}
+	return ret
+}
+
+func NewBroadcastFromAPI(b *api.BroadcastMessage) *Broadcast {
+	if b == nil || b.Id == "" || b.Limit == nil {
+		return nil
+	}
+
+	return &Broadcast{
+		Id: b.Id,
+		Payload: b.Payload,
+		Origin: NewContactInfoFromAPI(b.Origin),
+		Limit: *NewNodeIDFromAPI(b.Limit),
+		Ack: b.Ack,
+	}
+}
+
+func BroadcastAckToAPI(ack *BroadcastAck) *api.BroadcastAck {
+	return &api.BroadcastAck{
+		Delivered: uint32(ack.Delivered),
+		Unreachable: ContactInfoListToAPI(ack.Unreachable).Contacts,
+	}
+}
+
+func NewBroadcastAckFromAPI(ack *api.BroadcastAck) *BroadcastAck {
+	if ack == nil {
+		return nil
+	}
+
+	return &BroadcastAck{
+		Delivered: int(ack.Delivered),
+		Unreachable: NewContactInfoListFromAPI(&api.ContactInfoList{Contacts: ack.Unreachable}),
+	}
+}
+
+func TopicMessageToAPI(msg *TopicMessage) *api.TopicMessage {
+	ret := &api.TopicMessage{
+		Id: msg.Id,
+		Topic: msg.Topic,
+		Payload: msg.Payload,
+		Hops: uint32(msg.Hops),
+		Final: msg.Final,
+	}
+	if msg.Publisher != nil {
+		ret.Publisher = ContactInfoToAPI(msg.Publisher)
+	}
+	return ret
+}
+
+func NewTopicMessageFromAPI(msg *api.TopicMessage) *TopicMessage {
+	if msg == nil || msg.Id == "" || msg.Topic == "" {
+		return nil
+	}
+
+	return &TopicMessage{
+		Id: msg.Id,
+		Topic: msg.Topic,
+		Payload: msg.Payload,
+		Publisher: NewContactInfoFromAPI(msg.Publisher),
+		Hops: int(msg.Hops),
+		Final: msg.Final,
+	}
+}
+
+func AggregateToAPI(aggregate *Aggregate) *api.AggregateReply {
+	ret := &api.AggregateReply{
+		Nodes: uint64(aggregate.Nodes),
+		Keys: uint64(aggregate.Keys),
+		MinLoad: aggregate.MinLoad,
+		MaxLoad: aggregate.MaxLoad,
+		Versions: make(map[uint32]uint64),
+		Unreachable: ContactInfoListToAPI(aggregate.Unreachable).Contacts,
+	}
+	for version, count := range aggregate.Versions {
+		ret.Versions[version] = uint64(count)
+	}
+	return ret
+}
+
+func NewAggregateFromAPI(reply *api.AggregateReply) *Aggregate {
+	if reply == nil {
+		return nil
+	}
+
+	ret := &Aggregate{
+		Nodes: int(reply.Nodes),
+		Keys: int(reply.Keys),
+		MinLoad: reply.MinLoad,
+		MaxLoad: reply.MaxLoad,
+		Versions: make(map[uint32]int),
+		Unreachable: NewContactInfoListFromAPI(&api.ContactInfoList{Contacts: reply.Unreachable}),
+	}
+	for version, count := range reply.Versions {
+		ret.Versions[version] = int(count)
+	}
+	return ret
+}
@@ -2,7 +2,6 @@ package chord
 
 import (
 	"context"
-	"fmt"
 	"github.com/lukaspj/go-chord/api"
 )
 
@@ -13,60 +12,288 @@ type Service interface {
 	Predecessor(ctx context.Context) (*ContactInfo, error)
 	Successor(ctx context.Context) (*ContactInfo, error)
 	Notify(ctx context.Context, id *ContactInfo) error
+	SuccessorList(ctx context.Context) ([]*ContactInfo, error)
+	Store(ctx context.Context, item *Item) error
+	Fetch(ctx context.Context, key string) (*Item, error)
+	StoreHint(ctx context.Context, target *ContactInfo, item *Item) error
+	Load(ctx context.Context) (*LoadReport, error)
+	FindSuccessors(ctx context.Context, ids []NodeID) ([]*ContactInfo, error)
+	ProbeNode(ctx context.Context, target *ContactInfo) error
+	Gossip(ctx context.Context, heartbeats []Heartbeat) ([]Heartbeat, error)
+	Watch(ctx context.Context) (<-chan Neighbours, error)
+	Neighbours(ctx context.Context, notify *ContactInfo) (Neighbours, error)
+	RouteMessage(ctx context.Context, msg *Message) (*Reply, error)
+	HandleBroadcast(ctx context.Context, b *Broadcast) (*BroadcastAck, error)
+	JoinTopic(ctx context.Context, topic string, child *ContactInfo, final bool) error
+	LeaveTopic(ctx context.Context, topic string, child *ContactInfo) error
+	PublishMessage(ctx context.Context, msg *TopicMessage) error
+	Multicast(ctx context.Context, msg *TopicMessage) error
+	HandleAggregate(ctx context.Context, id string, limit NodeID) (*Aggregate, error)
+	HandleFindNodes(ctx context.Context, id string, limit NodeID, query Query) ([]*ContactInfo, error)
 }
 
 type ServiceWrapper struct {
 	service Service
 }
 
-func (w *ServiceWrapper) Ping(ctx context.Context, v *api.Void) (*api.ContactInfo, error) {
-	c, err := w.service.Ping(ctx)
+// contactReply answers with c. A call that succeeds always has a node to
+// answer with, so there is no empty reply.
+func contactReply(c *ContactInfo, err error) (*api.ContactInfo, error) {
+	if err != nil {
+		return nil, err
+	}
 	if c == nil {
-		return &api.ContactInfo{}, err
+		return nil, newError(Internal, "no node to answer with")
 	}
-	return ContactInfoToAPI(c), err
+	return ContactInfoToAPI(c), nil
+}
+
+func (w *ServiceWrapper) Ping(ctx context.Context, v *api.Void) (*api.ContactInfo, error) {
+	return contactReply(w.service.Ping(ctx))
 }
 
 func (w *ServiceWrapper) FindSuccessor(ctx context.Context, id *api.Id) (*api.ContactInfo, error) {
 	nid := NewNodeIDFromAPIId(id)
 	if nid == nil {
-		return &api.ContactInfo{}, fmt.Errorf("FindSuccessor id argument must not be nil.")
+		return nil, invalidArgument("id", "FindSuccessor id argument must not be nil.")
 	}
-	c, err := w.service.FindSuccessor(ctx, nid)
-	if c == nil {
-		return &api.ContactInfo{}, err
-	}
-	return ContactInfoToAPI(c), err
+	return contactReply(w.service.FindSuccessor(ctx, nid))
 }
 
 func (w *ServiceWrapper) ClosestPrecedingNode(ctx context.Context, id *api.Id) (*api.ContactInfo, error) {
 	nid := NewNodeIDFromAPIId(id)
 	if nid == nil {
-		return &api.ContactInfo{}, fmt.Errorf("ClosestPrecedingNode id argument must not be nil.")
-	}
-	c, err := w.service.ClosestPrecedingNode(ctx, nid)
-	if c == nil {
-		return &api.ContactInfo{}, err
+		return nil, invalidArgument("id", "ClosestPrecedingNode id argument must not be nil.")
 	}
-	return ContactInfoToAPI(c), err
+	return contactReply(w.service.ClosestPrecedingNode(ctx, nid))
 }
 
+// Predecessor serves peers from before CurrentPredecessor, with an empty
+// ContactInfo when there is no predecessor.
 func (w *ServiceWrapper) Predecessor(ctx context.Context, v *api.Void) (*api.ContactInfo, error) {
 	c, err := w.service.Predecessor(ctx)
+	if err != nil {
+		return nil, err
+	}
 	if c == nil {
-		return &api.ContactInfo{}, err
+		return &api.ContactInfo{}, nil
 	}
-	return ContactInfoToAPI(c), err
+	return ContactInfoToAPI(c), nil
 }
 
-func (w *ServiceWrapper) Successor(ctx context.Context, v *api.Void) (*api.ContactInfo, error) {
-	c, err := w.service.Successor(ctx)
+func (w *ServiceWrapper) CurrentPredecessor(ctx context.Context, v *api.Void) (*api.PredecessorReply, error) {
+	c, err := w.service.Predecessor(ctx)
+	if err != nil {
+		return nil, err
+	}
 	if c == nil {
-		return &api.ContactInfo{}, err
+		return &api.PredecessorReply{HasPredecessor: false}, nil
 	}
-	return ContactInfoToAPI(c), err
+	return &api.PredecessorReply{HasPredecessor: true, Predecessor: ContactInfoToAPI(c)}, nil
+}
+
+func (w *ServiceWrapper) Successor(ctx context.Context, v *api.Void) (*api.ContactInfo, error) {
+	return contactReply(w.service.Successor(ctx))
 }
 
 func (w *ServiceWrapper) Notify(ctx context.Context, ci *api.ContactInfo) (*api.Void, error) {
-	return &api.Void{},  w.service.Notify(ctx, NewContactInfoFromAPI(ci))
-}
\ No newline at end of file
+	sender := NewContactInfoFromAPI(ci)
+	if sender == nil {
+		return nil, invalidArgument("sender", "Notify sender argument must not be nil.")
+	}
+	return &api.Void{}, w.service.Notify(ctx, sender)
+}
+
+func (w *ServiceWrapper) SuccessorList(ctx context.Context, v *api.Void) (*api.ContactInfoList, error) {
+	l, err := w.service.SuccessorList(ctx)
+	return ContactInfoListToAPI(l), err
+}
+
+func (w *ServiceWrapper) Store(ctx context.Context, item *api.Item) (*api.Void, error) {
+	i := NewItemFromAPI(item)
+	if i == nil {
+		return nil, invalidArgument("item", "Store item argument must have a key.")
+	}
+	return &api.Void{}, w.service.Store(ctx, i)
+}
+
+func (w *ServiceWrapper) Fetch(ctx context.Context, key *api.Key) (*api.FetchReply, error) {
+	if key.Key == "" {
+		return nil, invalidArgument("key", "Fetch key argument must not be empty.")
+	}
+	i, err := w.service.Fetch(ctx, key.Key)
+	if err != nil {
+		return nil, err
+	}
+	if i == nil {
+		return &api.FetchReply{Found: false}, nil
+	}
+	return &api.FetchReply{Found: true, Item: ItemToAPI(i)}, nil
+}
+
+func (w *ServiceWrapper) StoreHint(ctx context.Context, hint *api.Hint) (*api.Void, error) {
+	target := NewContactInfoFromAPI(hint.Target)
+	if target == nil {
+		return nil, invalidArgument("target", "StoreHint target argument must not be nil.")
+	}
+	item := NewItemFromAPI(hint.Item)
+	if item == nil {
+		return nil, invalidArgument("item", "StoreHint item argument must have a key.")
+	}
+	return &api.Void{}, w.service.StoreHint(ctx, target, item)
+}
+
+func (w *ServiceWrapper) Load(ctx context.Context, v *api.Void) (*api.LoadReport, error) {
+	r, err := w.service.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return LoadReportToAPI(r), nil
+}
+
+func (w *ServiceWrapper) FindSuccessors(list *api.IdList, stream api.Chord_FindSuccessorsServer) error {
+	var ids []NodeID
+	for _, id := range list.Ids {
+		nid := NewNodeIDFromAPI(id)
+		if nid == nil {
+			return invalidArgument("ids", "FindSuccessors ids must not be nil.")
+		}
+		ids = append(ids, *nid)
+	}
+
+	res, err := w.service.FindSuccessors(stream.Context(), ids)
+	if err != nil {
+		return err
+	}
+	for i, c := range res {
+		lookup := &api.Lookup{Id: list.Ids[i]}
+		if c != nil {
+			lookup.Found = true
+			lookup.Successor = ContactInfoToAPI(c)
+		}
+		if err = stream.Send(lookup); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *ServiceWrapper) ProbeNode(ctx context.Context, ci *api.ContactInfo) (*api.Void, error) {
+	target := NewContactInfoFromAPI(ci)
+	if target == nil {
+		return nil, invalidArgument("target", "ProbeNode target argument must not be nil.")
+	}
+	return &api.Void{}, w.service.ProbeNode(ctx, target)
+}
+
+func (w *ServiceWrapper) Gossip(ctx context.Context, list *api.HeartbeatList) (*api.HeartbeatList, error) {
+	h, err := w.service.Gossip(ctx, NewHeartbeatsFromAPI(list))
+	return HeartbeatsToAPI(h), err
+}
+
+func (w *ServiceWrapper) Watch(v *api.Void, stream api.Chord_WatchServer) error {
+	updates, err := w.service.Watch(stream.Context())
+	if err != nil {
+		return err
+	}
+	for neighbours := range updates {
+		if err = stream.Send(NeighboursToAPI(neighbours)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *ServiceWrapper) Neighbours(ctx context.Context, req *api.NeighboursRequest) (*api.NeighbourUpdate, error) {
+	var notify *ContactInfo
+	if req.Notify != nil {
+		if notify = NewContactInfoFromAPI(req.Notify); notify == nil {
+			return nil, invalidArgument("notify", "Neighbours notify argument must have an id.")
+		}
+	}
+	n, err := w.service.Neighbours(ctx, notify)
+	if err != nil {
+		return nil, err
+	}
+	return NeighboursToAPI(n), nil
+}
+
+func (w *ServiceWrapper) Route(ctx context.Context, m *api.Message) (*api.Reply, error) {
+	msg := NewMessageFromAPI(m)
+	if msg == nil {
+		return nil, invalidArgument("key", "Route key argument must not be nil.")
+	}
+	r, err := w.service.RouteMessage(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+	return ReplyToAPI(r), nil
+}
+
+func (w *ServiceWrapper) Broadcast(ctx context.Context, m *api.BroadcastMessage) (*api.BroadcastAck, error) {
+	b := NewBroadcastFromAPI(m)
+	if b == nil {
+		return nil, invalidArgument("id", "Broadcast must have an id and a limit.")
+	}
+	ack, err := w.service.HandleBroadcast(ctx, b)
+	if err != nil {
+		return nil, err
+	}
+	return BroadcastAckToAPI(ack), nil
+}
+
+func (w *ServiceWrapper) JoinTopic(ctx context.Context, join *api.TopicJoin) (*api.Void, error) {
+	child := NewContactInfoFromAPI(join.Child)
+	if join.Topic == "" || child == nil {
+		return nil, invalidArgument("topic", "JoinTopic must have a topic and a child.")
+	}
+	return &api.Void{}, w.service.JoinTopic(ctx, join.Topic, child, join.Final)
+}
+
+func (w *ServiceWrapper) LeaveTopic(ctx context.Context, join *api.TopicJoin) (*api.Void, error) {
+	child := NewContactInfoFromAPI(join.Child)
+	if join.Topic == "" || child == nil {
+		return nil, invalidArgument("topic", "LeaveTopic must have a topic and a child.")
+	}
+	return &api.Void{}, w.service.LeaveTopic(ctx, join.Topic, child)
+}
+
+func (w *ServiceWrapper) Publish(ctx context.Context, m *api.TopicMessage) (*api.Void, error) {
+	msg := NewTopicMessageFromAPI(m)
+	if msg == nil {
+		return nil, invalidArgument("topic", "Publish must have an id and a topic.")
+	}
+	return &api.Void{}, w.service.PublishMessage(ctx, msg)
+}
+
+func (w *ServiceWrapper) Multicast(ctx context.Context, m *api.TopicMessage) (*api.Void, error) {
+	msg := NewTopicMessageFromAPI(m)
+	if msg == nil {
+		return nil, invalidArgument("topic", "Multicast must have an id and a topic.")
+	}
+	return &api.Void{}, w.service.Multicast(ctx, msg)
+}
+
+func (w *ServiceWrapper) Aggregate(ctx context.Context, req *api.AggregateRequest) (*api.AggregateReply, error) {
+	limit := NewNodeIDFromAPI(req.Limit)
+	if req.Id == "" || limit == nil {
+		return nil, invalidArgument("id", "Aggregate must have an id and a limit.")
+	}
+	a, err := w.service.HandleAggregate(ctx, req.Id, *limit)
+	if err != nil {
+		return nil, err
+	}
+	return AggregateToAPI(a), nil
+}
+
+func (w *ServiceWrapper) FindNodes(ctx context.Context, q *api.NodeQuery) (*api.ContactInfoList, error) {
+	limit := NewNodeIDFromAPI(q.Limit)
+	if q.Id == "" || limit == nil {
+		return nil, invalidArgument("id", "FindNodes must have an id and a limit.")
+	}
+	l, err := w.service.HandleFindNodes(ctx, q.Id, *limit, Query(q.Attributes))
+	if err != nil {
+		return nil, err
+	}
+	return ContactInfoListToAPI(l), nil
+}
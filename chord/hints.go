@@ -0,0 +1,95 @@
+package chord
+
+import (
+	"context"
+	"sync"
+)
+
+// hintStore keeps writes meant for replicas that could not be reached, keyed
+// by the id of the intended replica, until that replica answers again.
+type hintStore struct {
+	mutex   sync.Mutex
+	targets map[string]*ContactInfo
+	items   map[string]map[string]*Item
+}
+
+func newHintStore() *hintStore {
+	return &hintStore{
+		targets: make(map[string]*ContactInfo),
+		items:   make(map[string]map[string]*Item),
+	}
+}
+
+func (hints *hintStore) Add(target *ContactInfo, item *Item) {
+	hints.mutex.Lock()
+	defer hints.mutex.Unlock()
+
+	id := target.Id.String()
+	if hints.items[id] == nil {
+		hints.items[id] = make(map[string]*Item)
+	}
+	if current, ok := hints.items[id][item.Key]; ok && current.Version >= item.Version {
+		return
+	}
+	hints.targets[id] = target
+	hints.items[id][item.Key] = item
+}
+
+func (hints *hintStore) Targets() (targets []*ContactInfo) {
+	hints.mutex.Lock()
+	defer hints.mutex.Unlock()
+
+	for _, target := range hints.targets {
+		targets = append(targets, target)
+	}
+	sortContacts(targets)
+	return
+}
+
+// Take removes and returns every hint held for target.
+func (hints *hintStore) Take(target *ContactInfo) (items []*Item) {
+	hints.mutex.Lock()
+	defer hints.mutex.Unlock()
+
+	id := target.Id.String()
+	for _, item := range hints.items[id] {
+		items = append(items, item)
+	}
+	sortItems(items)
+	delete(hints.items, id)
+	delete(hints.targets, id)
+	return
+}
+
+// HandOff replays the hints for target, putting back any that fail.
+func (hints *hintStore) HandOff(network *chordNetwork, target *ContactInfo) {
+	items := hints.Take(target)
+	if len(items) == 0 {
+		return
+	}
+
+	logger.Info("handing off %d hinted items to %s", len(items), target.Address)
+	for _, item := range items {
+		if err := network.Store(target, item); err != nil {
+			logger.Warn("hinted handoff of %s to %s failed: %v", item.Key, target.Address, err)
+			hints.Add(target, item)
+		}
+	}
+}
+
+// ReplayHints pings every node we hold hints for and hands off to those that
+// answer.
+func (peer *Peer) ReplayHints() {
+	for _, target := range peer.hints.Targets() {
+		if info, err := peer.network.PingNode(target); err == nil && info.Id.Equals(target.Id) {
+			peer.hints.HandOff(peer.network, target)
+		}
+	}
+}
+
+func (peer *Peer) StoreHint(ctx context.Context, target *ContactInfo, item *Item) (err error) {
+	logger.Debug("StoreHint: %s@%d for %s", item.Key, item.Version, target.Address)
+	peer.hints.Add(target, item)
+
+	return
+}
@@ -0,0 +1,360 @@
+package chord
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net"
+
+	"github.com/lukaspj/go-chord/api"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// targetMetadataKey carries the id of the virtual node a request is meant for.
+const targetMetadataKey = "chord-target"
+
+// Host serves several virtual nodes behind a single gRPC listener. Each
+// virtual node is a Peer with its own id, finger table and successor list,
+// and requests are routed to it by the id in their metadata.
+type Host struct {
+	Info  *ContactInfo
+	Port  int
+	Peers []*Peer
+	// Faults, if set before the host starts serving, are injected into
+	// the RPCs it answers and into those of virtual nodes without faults
+	// of their own.
+	Faults *Faults
+	server *grpc.Server
+}
+
+// NewHost creates a host with weight virtual nodes, so larger machines can be
+// given a larger share of the ring. The first virtual node uses info's id,
+// the others derive theirs from it.
+func NewHost(info *ContactInfo, port int, weight int) (host *Host) {
+	if weight < 1 {
+		weight = 1
+	}
+
+	host = &Host{
+		Info: info,
+		Port: port,
+	}
+	for i := 0; i < weight; i++ {
+		vinfo := info
+		if i > 0 {
+			vinfo = &ContactInfo{
+				Address: info.Address,
+				Id:      NewNodeIDFromHash(fmt.Sprintf("%s#%d", info.Id.String(), i)),
+				Payload: info.Payload,
+			}
+		}
+		peer := NewPeer(vinfo, port)
+		host.Peers = append(host.Peers, &peer)
+	}
+
+	return
+}
+
+func (host *Host) Listen() error {
+	logger.Info("Listening on port: %d with %d virtual nodes", host.Port, len(host.Peers))
+
+	l, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", host.Port))
+	if err != nil {
+		return err
+	}
+	host.Serve(l)
+	host.Start()
+	return nil
+}
+
+// Serve answers RPCs for every virtual node on l in the background. Each
+// RPC is checked against what the virtual node it is meant for advertises.
+func (host *Host) Serve(l net.Listener) {
+	advertised := func(ctx context.Context) Capability {
+		if peer, err := host.Peer(ctx); err == nil {
+			return peer.GetInfo().Capabilities
+		}
+		// The call itself reports the unknown target
+		return AllCapabilities
+	}
+	host.server = grpc.NewServer(serverOptions(host.Faults, advertised)...)
+	api.RegisterChordServer(host.server, &ServiceWrapper{service: host})
+
+	go host.server.Serve(l)
+}
+
+// Start starts the virtual nodes, which form a ring of their own until the
+// host connects elsewhere.
+func (host *Host) Start() {
+	for _, peer := range host.Peers {
+		if peer.Faults == nil {
+			peer.Faults = host.Faults
+		}
+		peer.Start()
+	}
+
+	for _, peer := range host.Peers[1:] {
+		peer.Connect(host.Info.Address)
+	}
+}
+
+// Stop stops the virtual nodes and the server, as if the host had crashed.
+func (host *Host) Stop() {
+	for _, peer := range host.Peers {
+		peer.Stop()
+	}
+	if host.server != nil {
+		host.server.Stop()
+	}
+}
+
+// SetMetadata changes what every virtual node advertises.
+func (host *Host) SetMetadata(metadata Metadata) {
+	for _, peer := range host.Peers {
+		peer.SetMetadata(metadata)
+	}
+}
+
+// Connect joins every virtual node to the ring reachable through address.
+func (host *Host) Connect(address string) (err error) {
+	for _, peer := range host.Peers {
+		if e := peer.Connect(address); e != nil {
+			err = e
+		}
+	}
+	return
+}
+
+// Peer returns the virtual node addressed by the request, or the first one
+// if the request does not name a target.
+func (host *Host) Peer(ctx context.Context) (*Peer, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	targets := md.Get(targetMetadataKey)
+	if len(targets) == 0 || targets[0] == "" {
+		return host.Peers[0], nil
+	}
+
+	val, err := hex.DecodeString(targets[0])
+	if err != nil {
+		return nil, invalidArgument(targetMetadataKey, "malformed target id %q: %v", targets[0], err)
+	}
+	target := NodeID{Val: val}
+	for _, peer := range host.Peers {
+		if peer.GetInfo().Id.Equals(target) {
+			return peer, nil
+		}
+	}
+	return nil, newError(NotFound, "no virtual node with id %s on this host", target.String())
+}
+
+func (host *Host) Ping(ctx context.Context) (*ContactInfo, error) {
+	peer, err := host.Peer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return peer.Ping(ctx)
+}
+
+func (host *Host) FindSuccessor(ctx context.Context, id *NodeID) (*ContactInfo, error) {
+	peer, err := host.Peer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return peer.FindSuccessor(ctx, id)
+}
+
+func (host *Host) ClosestPrecedingNode(ctx context.Context, id *NodeID) (*ContactInfo, error) {
+	peer, err := host.Peer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return peer.ClosestPrecedingNode(ctx, id)
+}
+
+func (host *Host) Predecessor(ctx context.Context) (*ContactInfo, error) {
+	peer, err := host.Peer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return peer.Predecessor(ctx)
+}
+
+func (host *Host) Successor(ctx context.Context) (*ContactInfo, error) {
+	peer, err := host.Peer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return peer.Successor(ctx)
+}
+
+func (host *Host) Notify(ctx context.Context, sender *ContactInfo) error {
+	peer, err := host.Peer(ctx)
+	if err != nil {
+		return err
+	}
+	return peer.Notify(ctx, sender)
+}
+
+func (host *Host) SuccessorList(ctx context.Context) ([]*ContactInfo, error) {
+	peer, err := host.Peer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return peer.SuccessorList(ctx)
+}
+
+func (host *Host) Store(ctx context.Context, item *Item) error {
+	peer, err := host.Peer(ctx)
+	if err != nil {
+		return err
+	}
+	return peer.Store(ctx, item)
+}
+
+func (host *Host) Fetch(ctx context.Context, key string) (*Item, error) {
+	peer, err := host.Peer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return peer.Fetch(ctx, key)
+}
+
+func (host *Host) StoreHint(ctx context.Context, target *ContactInfo, item *Item) error {
+	peer, err := host.Peer(ctx)
+	if err != nil {
+		return err
+	}
+	return peer.StoreHint(ctx, target, item)
+}
+
+func (host *Host) Load(ctx context.Context) (*LoadReport, error) {
+	peer, err := host.Peer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return peer.Load(ctx)
+}
+
+func (host *Host) FindSuccessors(ctx context.Context, ids []NodeID) ([]*ContactInfo, error) {
+	peer, err := host.Peer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return peer.FindSuccessors(ctx, ids)
+}
+
+func (host *Host) ProbeNode(ctx context.Context, target *ContactInfo) error {
+	peer, err := host.Peer(ctx)
+	if err != nil {
+		return err
+	}
+	return peer.ProbeNode(ctx, target)
+}
+
+func (host *Host) Gossip(ctx context.Context, heartbeats []Heartbeat) ([]Heartbeat, error) {
+	peer, err := host.Peer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return peer.Gossip(ctx, heartbeats)
+}
+
+func (host *Host) Watch(ctx context.Context) (<-chan Neighbours, error) {
+	peer, err := host.Peer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return peer.Watch(ctx)
+}
+
+func (host *Host) Neighbours(ctx context.Context, notify *ContactInfo) (Neighbours, error) {
+	peer, err := host.Peer(ctx)
+	if err != nil {
+		return Neighbours{}, err
+	}
+	return peer.Neighbours(ctx, notify)
+}
+
+func (host *Host) RouteMessage(ctx context.Context, msg *Message) (*Reply, error) {
+	peer, err := host.Peer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return peer.RouteMessage(ctx, msg)
+}
+
+func (host *Host) HandleBroadcast(ctx context.Context, b *Broadcast) (*BroadcastAck, error) {
+	peer, err := host.Peer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return peer.HandleBroadcast(ctx, b)
+}
+
+func (host *Host) JoinTopic(ctx context.Context, topic string, child *ContactInfo, final bool) error {
+	peer, err := host.Peer(ctx)
+	if err != nil {
+		return err
+	}
+	return peer.JoinTopic(ctx, topic, child, final)
+}
+
+func (host *Host) LeaveTopic(ctx context.Context, topic string, child *ContactInfo) error {
+	peer, err := host.Peer(ctx)
+	if err != nil {
+		return err
+	}
+	return peer.LeaveTopic(ctx, topic, child)
+}
+
+func (host *Host) PublishMessage(ctx context.Context, msg *TopicMessage) error {
+	peer, err := host.Peer(ctx)
+	if err != nil {
+		return err
+	}
+	return peer.PublishMessage(ctx, msg)
+}
+
+func (host *Host) Multicast(ctx context.Context, msg *TopicMessage) error {
+	peer, err := host.Peer(ctx)
+	if err != nil {
+		return err
+	}
+	return peer.Multicast(ctx, msg)
+}
+
+func (host *Host) HandleAggregate(ctx context.Context, id string, limit NodeID) (*Aggregate, error) {
+	peer, err := host.Peer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return peer.HandleAggregate(ctx, id, limit)
+}
+
+func (host *Host) HandleFindNodes(ctx context.Context, id string, limit NodeID, query Query) ([]*ContactInfo, error) {
+	peer, err := host.Peer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return peer.HandleFindNodes(ctx, id, limit, query)
+}
+
+func withTarget(ctx context.Context, target NodeID) context.Context {
+	if target.IsZero() {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, targetMetadataKey, hex.EncodeToString(target.Val))
+}
+
+func targetUnaryInterceptor(target NodeID) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(withTarget(ctx, target), method, req, reply, cc, opts...)
+	}
+}
+
+func targetStreamInterceptor(target NodeID) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(withTarget(ctx, target), desc, cc, method, opts...)
+	}
+}
@@ -0,0 +1,118 @@
+package chord
+
+import (
+	"net"
+	"sort"
+	"testing"
+)
+
+func TestHostRefusesWhatVirtualNodeLacks(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := l.Addr().String()
+	host := NewHost(&ContactInfo{Address: address, Id: NewNodeIDFromHash(address)}, l.Addr().(*net.TCPAddr).Port, 2)
+	old := host.Peers[1]
+	old.Version = 0
+	old.Capabilities = 0
+	host.Serve(l)
+	host.Start()
+	defer host.Stop()
+
+	client := NewChordNetwork(&ContactInfo{Id: NewEmptyNodeID()})
+	// Ask with every capability advertised, so it is the host that refuses
+	for _, peer := range host.Peers {
+		target := *peer.GetInfo()
+		target.Version = ProtocolVersion
+		target.Capabilities = AllCapabilities
+		_, err = client.SuccessorList(&target)
+		switch {
+		case peer == old && ErrorKindOf(err) != Unsupported:
+			t.Errorf("SuccessorList of the old virtual node returned %v, expected it to be unsupported", err)
+		case peer != old && err != nil:
+			t.Errorf("SuccessorList of the new virtual node failed: %v", err)
+		}
+	}
+}
+
+func TestHostWeightSetsVirtualNodes(t *testing.T) {
+	for _, weight := range []int{1, 3, 8} {
+		host := NewHost(&ContactInfo{Address: "host", Id: NewNodeIDFromHash("host")}, 0, weight)
+		if len(host.Peers) != weight {
+			t.Errorf("host of weight %d has %d virtual nodes", weight, len(host.Peers))
+		}
+		ids := map[string]bool{}
+		for _, peer := range host.Peers {
+			if peer.GetInfo().Address != "host" {
+				t.Errorf("virtual node advertises %s, expected the host's address", peer.GetInfo().Address)
+			}
+			ids[peer.GetInfo().Id.String()] = true
+		}
+		if len(ids) != weight {
+			t.Errorf("host of weight %d has %d distinct ids", weight, len(ids))
+		}
+	}
+
+	if host := NewHost(&ContactInfo{Address: "host", Id: NewNodeIDFromHash("host")}, 0, 0); len(host.Peers) != 1 {
+		t.Errorf("host of weight 0 has %d virtual nodes, expected 1", len(host.Peers))
+	}
+}
+
+// Each virtual node owns the arc from its predecessor, so a host with more of
+// them is responsible for more of the ring.
+func TestHeavierHostOwnsMoreOfTheRing(t *testing.T) {
+	light := NewHost(&ContactInfo{Address: "light", Id: NewNodeIDFromHash("light")}, 0, 1)
+	heavy := NewHost(&ContactInfo{Address: "heavy", Id: NewNodeIDFromHash("heavy")}, 0, 8)
+
+	var nodes []*ContactInfo
+	for _, peer := range append(light.Peers, heavy.Peers...) {
+		nodes = append(nodes, peer.GetInfo())
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Id.Less(nodes[j].Id) })
+	share := map[string]float64{}
+	for i, node := range nodes {
+		pred := nodes[(i+len(nodes)-1)%len(nodes)]
+		share[node.Address] += pred.Id.ArcFraction(node.Id)
+	}
+
+	if share["heavy"] <= share["light"] {
+		t.Errorf("host of weight 8 owns %.2f of the ring, the one of weight 1 owns %.2f", share["heavy"], share["light"])
+	}
+}
+
+func TestHostRoutesByTarget(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := l.Addr().String()
+	host := NewHost(&ContactInfo{Address: address, Id: NewNodeIDFromHash(address)}, l.Addr().(*net.TCPAddr).Port, 3)
+	host.Serve(l)
+	defer host.Stop()
+
+	client := NewChordNetwork(&ContactInfo{Id: NewEmptyNodeID()})
+	for _, peer := range host.Peers {
+		info, err := client.PingNode(peer.GetInfo())
+		if err != nil {
+			t.Fatalf("ping of %s failed: %v", peer.GetInfo().Id.String(), err)
+		}
+		if !info.Id.Equals(peer.GetInfo().Id) {
+			t.Errorf("ping of %s was answered by %s", peer.GetInfo().Id.String(), info.Id.String())
+		}
+	}
+
+	// Without a target the first virtual node answers
+	info, err := client.Ping(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.Id.Equals(host.Peers[0].GetInfo().Id) {
+		t.Errorf("ping without a target was answered by %s, expected %s", info.Id.String(), host.Peers[0].GetInfo().Id.String())
+	}
+
+	_, err = client.PingNode(&ContactInfo{Address: address, Id: NewNodeIDFromHash("elsewhere")})
+	if ErrorKindOf(err) != NotFound {
+		t.Errorf("ping of an id the host does not serve returned %v, expected it not to be found", err)
+	}
+}
@@ -0,0 +1,248 @@
+package chord
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+const loadBalancingInterval = time.Minute
+
+// A node moves when its load is this many times that of its successor.
+const loadImbalanceFactor = 2.0
+
+// Below this many requests per second arc sizes are compared instead.
+const minRebalanceRate = 1.0
+
+// LoadReport is what a node tells its neighbours about its share of the ring.
+type LoadReport struct {
+	Id          NodeID  `json:"id"`
+	Arc         float64 `json:"arc"`
+	RequestRate float64 `json:"request_rate"`
+	Keys        int     `json:"keys"`
+}
+
+// loadTracker counts key requests over windows of loadBalancingInterval.
+type loadTracker struct {
+	mutex       sync.Mutex
+	clock       Clock
+	windowStart time.Time
+	hits        map[string]uint64
+	rate        float64
+	lastHits    map[string]uint64
+}
+
+func newLoadTracker() *loadTracker {
+	return &loadTracker{
+		clock:       realClock{},
+		windowStart: time.Now(),
+		hits:        make(map[string]uint64),
+	}
+}
+
+func (tracker *loadTracker) Record(key string) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+	tracker.hits[key]++
+}
+
+// Roll closes the current window, making its request rate and per-key hits
+// the ones reported until the next roll.
+func (tracker *loadTracker) Roll() {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	var total uint64
+	for _, hits := range tracker.hits {
+		total += hits
+	}
+	now := tracker.clock.Now()
+	elapsed := now.Sub(tracker.windowStart).Seconds()
+	if elapsed > 0 {
+		tracker.rate = float64(total) / elapsed
+	}
+	tracker.lastHits = tracker.hits
+	tracker.hits = make(map[string]uint64)
+	tracker.windowStart = now
+}
+
+func (tracker *loadTracker) Rate() float64 {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+	return tracker.rate
+}
+
+func (tracker *loadTracker) Hits() map[string]uint64 {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+	return tracker.lastHits
+}
+
+func (peer *Peer) Load(ctx context.Context) (report *LoadReport, err error) {
+	logger.Debug("Load")
+	report = &LoadReport{
+		Id:          peer.GetInfo().Id,
+		RequestRate: peer.load.Rate(),
+		Keys:        peer.store.Len(),
+	}
+	if pred := peer.GetPredecessor(); pred != nil {
+		report.Arc = pred.Id.ArcFraction(peer.GetInfo().Id)
+	}
+
+	return
+}
+
+// BalanceLoad compares this node's load with its successor's and, if it is
+// carrying much more, moves the node so the successor takes over the upper
+// part of its range. Request rates are compared when there is traffic to
+// speak of, arc sizes otherwise.
+func (peer *Peer) BalanceLoad() (err error) {
+	peer.load.Roll()
+
+	pred := peer.GetPredecessor()
+	succ := peer.GetSuccessor()
+	if pred == nil || succ == nil || succ.Id.Equals(peer.GetInfo().Id) {
+		return
+	}
+
+	var own, remote *LoadReport
+	own, _ = peer.Load(context.Background())
+	if remote, err = peer.network.Load(succ); err != nil {
+		return
+	}
+
+	var split NodeID
+	if own.RequestRate >= minRebalanceRate {
+		if own.RequestRate <= loadImbalanceFactor*remote.RequestRate {
+			return
+		}
+		var ok bool
+		if split, ok = peer.hotSplit(pred.Id); !ok {
+			logger.Info("load is concentrated on a single key, not moving")
+			return
+		}
+	} else {
+		if own.Arc <= loadImbalanceFactor*remote.Arc {
+			return
+		}
+		split = pred.Id.Midpoint(peer.GetInfo().Id)
+	}
+
+	logger.Info("load %.2f/%.4f against successor's %.2f/%.4f, moving to %s",
+		own.RequestRate, own.Arc, remote.RequestRate, remote.Arc, split.String())
+	return peer.Move(split)
+}
+
+// hotSplit picks the id that leaves about half of the last window's key
+// requests in (pred, split] and the rest above it.
+func (peer *Peer) hotSplit(pred NodeID) (split NodeID, ok bool) {
+	type hotKey struct {
+		id   NodeID
+		hits uint64
+	}
+
+	var keys []hotKey
+	var total uint64
+	for key, hits := range peer.load.Hits() {
+		id := NewNodeIDFromHash(key)
+		if id.Between(pred, peer.GetInfo().Id) {
+			keys = append(keys, hotKey{id: id, hits: hits})
+			total += hits
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return pred.Distance(keys[i].id).Cmp(pred.Distance(keys[j].id)) < 0
+	})
+
+	var sum uint64
+	for i, key := range keys {
+		sum += key.hits
+		if 2*sum >= total {
+			if i == len(keys)-1 {
+				return
+			}
+			return key.id, true
+		}
+	}
+	return
+}
+
+// Move re-joins the ring at id, which must lie between the predecessor and
+// the current id. The items the successor becomes responsible for are handed
+// over to every replica of their new preference list, the last of which did
+// not hold them before, and are only dropped here once the node has taken
+// its new place.
+func (peer *Peer) Move(id NodeID) (err error) {
+	pred := peer.GetPredecessor()
+	succ := peer.GetSuccessor()
+	if pred == nil {
+		return newError(FailedPrecondition, "cannot move without a predecessor")
+	}
+	if id.Equals(peer.GetInfo().Id) || !id.Between(pred.Id, peer.GetInfo().Id) {
+		return invalidArgument("id", "%s is not between predecessor %s and %s", id.String(), pred.Id.String(), peer.GetInfo().Id.String())
+	}
+
+	var moving []*Item
+	for _, item := range peer.store.Items() {
+		if NewNodeIDFromHash(item.Key).Between(id, peer.GetInfo().Id) {
+			moving = append(moving, item)
+		}
+	}
+
+	if len(moving) > 0 {
+		var replicas []*ContactInfo
+		if replicas, err = peer.successorReplicas(succ); err != nil {
+			return
+		}
+		for _, item := range moving {
+			for _, replica := range replicas {
+				if err = peer.network.Store(replica, item); err != nil {
+					return &Error{Kind: Unavailable, Node: replica, Message: fmt.Sprintf("failed to hand %s over: %v", item.Key, err)}
+				}
+			}
+		}
+	}
+
+	logger.Info("Moving from %s to %s", peer.GetInfo().Id.String(), id.String())
+	info := *peer.GetInfo()
+	info.Id = id
+	peer.setInfo(&info)
+	peer.network.fingerTable.Clear()
+	peer.network.changed()
+
+	// The successor checks that our old id is gone before taking us as its
+	// predecessor, it would not otherwise accept a lower id
+	err = peer.network.Notify(succ)
+	peer.Poke()
+
+	for _, item := range moving {
+		peer.store.Delete(item.Key)
+	}
+	return
+}
+
+// successorReplicas returns the preference list of the keys succ becomes
+// responsible for when this node moves away from in front of it.
+func (peer *Peer) successorReplicas(succ *ContactInfo) (replicas []*ContactInfo, err error) {
+	var successors []*ContactInfo
+	if successors, err = peer.network.successorsOf(succ); err != nil {
+		return nil, &Error{Kind: Unavailable, Node: succ, Message: fmt.Sprintf("failed to learn the successors of %s: %v", succ.Address, err)}
+	}
+
+	self := peer.GetInfo().Id
+	nodes := []*ContactInfo{succ}
+	for _, node := range successors {
+		if !node.Id.Equals(self) {
+			nodes = append(nodes, node)
+		}
+	}
+	replicas, _ = peer.network.preferenceList(nodes, peer.Quorum.N)
+	return
+}
+
+// setInfo replaces what the peer advertises about itself.
+func (peer *Peer) setInfo(info *ContactInfo) {
+	peer.network.setSelf(info)
+}
@@ -0,0 +1,78 @@
+package chord_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/lukaspj/go-chord/chord"
+	"github.com/lukaspj/go-chord/chordtest"
+)
+
+func TestMoveKeepsRingConsistent(t *testing.T) {
+	ring := startRing(t, 5, nil)
+	defer ring.Stop()
+
+	ctx := context.Background()
+	items := make(map[string][]byte)
+	for i := 0; i < 20; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		items[key] = []byte(fmt.Sprintf("value-%d", i))
+		if err := ring.Peers[0].Put(ctx, key, items[key]); err != nil {
+			t.Fatalf("put of %s failed: %v", key, err)
+		}
+	}
+
+	peer := ring.Running()[2]
+	old := peer.GetInfo().Id
+	id := peer.GetPredecessor().Id.Midpoint(old)
+	if err := peer.Move(id); err != nil {
+		t.Fatalf("move failed: %v", err)
+	}
+	if !peer.GetInfo().Id.Equals(id) {
+		t.Fatalf("peer is at %s after moving to %s", peer.GetInfo().Id.String(), id.String())
+	}
+
+	if err := ring.WaitForConvergence(convergenceTimeout); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every replica of the keys the successor took over holds them, before
+	// a read could repair any that does not
+	running := ring.Running()
+	for key := range items {
+		if !chord.NewNodeIDFromHash(key).Between(id, old) {
+			continue
+		}
+		first := ring.Responsible(chord.NewNodeIDFromHash(key))
+		for i, p := range running {
+			if p != first {
+				continue
+			}
+			for j := 0; j < chord.DefaultQuorum.N; j++ {
+				replica := running[(i+j)%len(running)]
+				if item, _ := replica.Fetch(ctx, key); item == nil {
+					t.Errorf("replica %d of %s, %s, does not hold it after the move", j, key, replica.GetInfo().Address)
+				}
+			}
+		}
+	}
+
+	chordtest.RingIsConsistent(t, ring)
+	chordtest.AllKeysPresent(t, ring, items)
+
+	// Neither the node nor its successor may take the old id for a member
+	var succ *chord.Peer
+	for _, p := range ring.Running() {
+		if p.GetInfo().Id.Equals(peer.GetSuccessor().Id) {
+			succ = p
+		}
+	}
+	for _, p := range []*chord.Peer{peer, succ} {
+		for _, member := range p.Members() {
+			if member.Info.Id.Equals(old) && member.State == chord.Alive {
+				t.Errorf("%s still has the old id %s as an alive member", p.GetInfo().Address, old.String())
+			}
+		}
+	}
+}
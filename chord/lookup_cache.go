@@ -0,0 +1,132 @@
+package chord
+
+import (
+	"math/big"
+	"sync"
+)
+
+const lookupCacheSize = 1024
+
+// cacheEntry says that node is responsible for the ids in (start, node.Id].
+type cacheEntry struct {
+	start NodeID
+	node  *ContactInfo
+}
+
+// lookupCache maps id ranges to the node responsible for them. It is filled
+// from lookup results and successor lists, and emptied whenever our own
+// routing state changes. A nil cache is valid and never hits.
+type lookupCache struct {
+	mutex   sync.Mutex
+	entries []cacheEntry
+	size    int
+}
+
+func newLookupCache(size int) *lookupCache {
+	return &lookupCache{
+		size: size,
+	}
+}
+
+func (cache *lookupCache) Lookup(id NodeID) *ContactInfo {
+	if cache == nil {
+		return nil
+	}
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	for i := len(cache.entries) - 1; i >= 0; i-- {
+		entry := cache.entries[i]
+		if !entry.start.Equals(entry.node.Id) && id.Between(entry.start, entry.node.Id) {
+			return entry.node
+		}
+	}
+	return nil
+}
+
+// Add records that node is responsible for (start, node.Id]. An existing
+// entry for the same node is kept if it covers a wider range.
+func (cache *lookupCache) Add(start NodeID, node *ContactInfo) {
+	if cache == nil || node == nil {
+		return
+	}
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	for i, entry := range cache.entries {
+		if entry.node.Id.Equals(node.Id) {
+			if entry.start.Distance(node.Id).Cmp(start.Distance(node.Id)) >= 0 {
+				return
+			}
+			cache.entries = append(cache.entries[:i], cache.entries[i+1:]...)
+			break
+		}
+	}
+
+	cache.entries = append(cache.entries, cacheEntry{start: start, node: node})
+	if len(cache.entries) > cache.size {
+		cache.entries = cache.entries[len(cache.entries)-cache.size:]
+	}
+}
+
+// AddLookup records that a lookup of id resolved to node, which tells us
+// node is responsible for at least [id, node.Id].
+func (cache *lookupCache) AddLookup(id NodeID, node *ContactInfo) {
+	if cache == nil || node == nil {
+		return
+	}
+	before := big.NewInt(0).Sub(id.BigInt(), big.NewInt(1))
+	before.Mod(before, ringModulus())
+	cache.Add(NodeID{Val: before.Bytes()}, node)
+}
+
+// AddSuccessors records the ranges implied by owner's successor list.
+func (cache *lookupCache) AddSuccessors(owner *ContactInfo, successors []*ContactInfo) {
+	if cache == nil {
+		return
+	}
+	prev := owner.Id
+	for _, succ := range successors {
+		if succ == nil || succ.Id.Equals(prev) || succ.Id.Equals(owner.Id) {
+			continue
+		}
+		cache.Add(prev, succ)
+		prev = succ.Id
+	}
+}
+
+// Remove drops the entries pointing at the node with the given id.
+func (cache *lookupCache) Remove(id NodeID) {
+	cache.filter(func(entry cacheEntry) bool {
+		return !entry.node.Id.Equals(id)
+	})
+}
+
+// Forget drops every entry pointing at address, after an RPC to it failed.
+func (cache *lookupCache) Forget(address string) {
+	cache.filter(func(entry cacheEntry) bool {
+		return entry.node.Address != address
+	})
+}
+
+func (cache *lookupCache) Clear() {
+	cache.filter(func(entry cacheEntry) bool {
+		return false
+	})
+}
+
+func (cache *lookupCache) filter(keep func(entry cacheEntry) bool) {
+	if cache == nil {
+		return
+	}
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	entries := cache.entries[:0]
+	for _, entry := range cache.entries {
+		if keep(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	cache.entries = entries
+}
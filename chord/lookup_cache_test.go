@@ -0,0 +1,81 @@
+package chord
+
+import (
+	"math/big"
+	"testing"
+)
+
+// before returns the id 2^exponent before id on the ring.
+func before(id NodeID, exponent uint) NodeID {
+	val := big.NewInt(0).Sub(id.BigInt(), big.NewInt(0).Lsh(big.NewInt(1), exponent))
+	val.Mod(val, ringModulus())
+	return NodeID{Val: val.Bytes()}
+}
+
+func TestLookupCacheHits(t *testing.T) {
+	cache := newLookupCache(lookupCacheSize)
+	node := &ContactInfo{Address: "node", Id: NewNodeIDFromHash("node")}
+	cache.Add(before(node.Id, 200), node)
+
+	if found := cache.Lookup(before(node.Id, 100)); found != node {
+		t.Errorf("lookup in the cached range found %v, expected %s", found, node.Address)
+	}
+	if found := cache.Lookup(node.Id); found != node {
+		t.Errorf("lookup of the node's own id found %v, expected %s", found, node.Address)
+	}
+	if found := cache.Lookup(before(node.Id, 201)); found != nil {
+		t.Errorf("lookup before the cached range found %s", found.Address)
+	}
+
+	cache.Forget(node.Address)
+	if found := cache.Lookup(node.Id); found != nil {
+		t.Errorf("lookup after forgetting the node found %s", found.Address)
+	}
+}
+
+func TestLookupCacheEvictsOldest(t *testing.T) {
+	cache := newLookupCache(2)
+	var nodes []*ContactInfo
+	for _, name := range []string{"first", "second", "third"} {
+		node := &ContactInfo{Address: name, Id: NewNodeIDFromHash(name)}
+		cache.Add(before(node.Id, 100), node)
+		nodes = append(nodes, node)
+	}
+
+	if found := cache.Lookup(nodes[0].Id); found != nil {
+		t.Errorf("the oldest entry is kept past the cache size, found %s", found.Address)
+	}
+	for _, node := range nodes[1:] {
+		if found := cache.Lookup(node.Id); found != node {
+			t.Errorf("lookup of %s found %v", node.Address, found)
+		}
+	}
+}
+
+func TestCachedSuccessorIsChecked(t *testing.T) {
+	cached := listeningPeer(t)
+	defer cached.Stop()
+	info := cached.GetInfo()
+	id := before(info.Id, 200)
+
+	p := NewPeer(&ContactInfo{Address: "client", Id: NewNodeIDFromHash("client")}, 0)
+	peer := &p
+	peer.LookupCache = true
+	peer.Reset()
+
+	// Its predecessor is before id, so it is still responsible
+	cached.network.setPredecessor(&ContactInfo{Address: "before", Id: before(info.Id, 201)})
+	peer.network.cache.Add(before(info.Id, 202), info)
+	if found := peer.cachedSuccessor(id); found == nil || !found.Id.Equals(info.Id) {
+		t.Fatalf("cached successor is %v, expected %s", found, info.Address)
+	}
+
+	// A node has joined between id and it, so the entry is stale
+	cached.network.setPredecessor(&ContactInfo{Address: "joined", Id: before(info.Id, 150)})
+	if found := peer.cachedSuccessor(id); found != nil {
+		t.Errorf("stale entry was used, found %s", found.Address)
+	}
+	if found := peer.network.cache.Lookup(id); found != nil {
+		t.Errorf("stale entry is still cached after the check failed")
+	}
+}
@@ -0,0 +1,289 @@
+package chord
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+const gossipInterval = 2 * time.Second
+
+// A member whose heartbeat has not increased for this long is suspect, and
+// after memberDeadTimeout it is considered dead.
+const memberSuspectTimeout = 20 * time.Second
+const memberDeadTimeout = time.Minute
+
+// Dead members are kept as tombstones for this long, which is well past the
+// few gossip rounds a heartbeat needs to reach every node, so that a stale
+// heartbeat still going around cannot bring them back. They are no longer
+// gossiped.
+const memberTombstonePeriod = time.Minute
+
+// This many of the members dropped after their tombstone period are
+// remembered, to look for them again after a partition that lasts longer.
+const formerMemberLimit = 32
+
+// Heartbeat is a node's gossiped liveness counter, which it increments every
+// gossip round.
+type Heartbeat struct {
+	Info  *ContactInfo `json:"info"`
+	Count uint64       `json:"count"`
+}
+
+// Member is an entry of the approximate membership view.
+type Member struct {
+	Info     *ContactInfo `json:"info"`
+	State    NodeState    `json:"state"`
+	LastSeen time.Time    `json:"last_seen"`
+}
+
+type memberEntry struct {
+	info    *ContactInfo
+	count   uint64
+	updated time.Time
+}
+
+// membership is a gossip-style membership view: heartbeats are spread by
+// push-pull gossip, and a member is suspected once its heartbeat stops
+// increasing.
+type membership struct {
+	mutex   sync.Mutex
+	clock   Clock
+	self    *ContactInfo
+	count   uint64
+	entries map[string]*memberEntry
+	// former are the members most recently dropped, oldest first.
+	former []*ContactInfo
+}
+
+func newMembership(self *ContactInfo) *membership {
+	return &membership{
+		clock:   realClock{},
+		self:    self,
+		entries: make(map[string]*memberEntry),
+	}
+}
+
+func (members *membership) Beat() {
+	members.mutex.Lock()
+	defer members.mutex.Unlock()
+	members.count++
+}
+
+func (members *membership) Heartbeats() (heartbeats []Heartbeat) {
+	members.mutex.Lock()
+	defer members.mutex.Unlock()
+
+	now := members.clock.Now()
+	heartbeats = append(heartbeats, Heartbeat{Info: members.self, Count: members.count})
+	for _, entry := range members.entries {
+		if now.Sub(entry.updated) < memberDeadTimeout {
+			heartbeats = append(heartbeats, Heartbeat{Info: entry.info, Count: entry.count})
+		}
+	}
+	return
+}
+
+// Merge takes every heartbeat that is newer than what we know, and drops
+// the tombstones that have served their time.
+func (members *membership) Merge(heartbeats []Heartbeat) {
+	members.mutex.Lock()
+	defer members.mutex.Unlock()
+
+	now := members.clock.Now()
+	var dropped []*ContactInfo
+	for id, entry := range members.entries {
+		if now.Sub(entry.updated) >= memberDeadTimeout+memberTombstonePeriod {
+			dropped = append(dropped, entry.info)
+			delete(members.entries, id)
+		}
+	}
+	sortContacts(dropped)
+	for _, info := range dropped {
+		members.remember(info)
+	}
+
+	for _, hb := range heartbeats {
+		if hb.Info == nil || hb.Info.Id.Equals(members.self.Id) {
+			continue
+		}
+		entry, ok := members.entries[hb.Info.Id.String()]
+		if !ok {
+			members.forgetFormer(hb.Info.Id)
+			members.entries[hb.Info.Id.String()] = &memberEntry{info: hb.Info, count: hb.Count, updated: now}
+		} else if hb.Count > entry.count {
+			entry.info, entry.count, entry.updated = hb.Info, hb.Count, now
+		}
+	}
+}
+
+// remember adds info to the former members, dropping the oldest one past
+// formerMemberLimit.
+func (members *membership) remember(info *ContactInfo) {
+	members.forgetFormer(info.Id)
+	members.former = append(members.former, info)
+	if len(members.former) > formerMemberLimit {
+		members.former = members.former[len(members.former)-formerMemberLimit:]
+	}
+}
+
+func (members *membership) forgetFormer(id NodeID) {
+	for i, info := range members.former {
+		if info.Id.Equals(id) {
+			members.former = append(members.former[:i:i], members.former[i+1:]...)
+			return
+		}
+	}
+}
+
+// Former returns the members that were dropped from the view, which may
+// still be alive on the other side of a partition.
+func (members *membership) Former() []*ContactInfo {
+	members.mutex.Lock()
+	defer members.mutex.Unlock()
+	return append([]*ContactInfo(nil), members.former...)
+}
+
+// SetSelf replaces what we advertise about ourselves. When the id changes,
+// the old one is kept as a dead member, so that the heartbeats for it still
+// going around do not bring it back.
+func (members *membership) SetSelf(info *ContactInfo) {
+	members.mutex.Lock()
+	defer members.mutex.Unlock()
+
+	if !info.Id.Equals(members.self.Id) {
+		members.entries[members.self.Id.String()] = &memberEntry{
+			info:    members.self,
+			count:   members.count,
+			updated: members.clock.Now().Add(-memberDeadTimeout),
+		}
+	}
+	members.self = info
+}
+
+// Forget declares info dead at once, for nodes known to be gone rather than
+// suspected to be.
+func (members *membership) Forget(info *ContactInfo) {
+	members.mutex.Lock()
+	defer members.mutex.Unlock()
+
+	entry, ok := members.entries[info.Id.String()]
+	if !ok {
+		entry = &memberEntry{info: info}
+		members.entries[info.Id.String()] = entry
+	}
+	entry.updated = members.clock.Now().Add(-memberDeadTimeout)
+}
+
+// Observe adds a node we learned about from routing traffic.
+func (members *membership) Observe(info *ContactInfo) {
+	if info == nil || info.Id.IsZero() {
+		return
+	}
+	members.Merge([]Heartbeat{{Info: info, Count: 0}})
+}
+
+func (members *membership) state(entry *memberEntry, detector *failureDetector) NodeState {
+	state := detector.State(entry.info.Id)
+	if since := members.clock.Now().Sub(entry.updated); since >= memberDeadTimeout {
+		state = Dead
+	} else if since >= memberSuspectTimeout && state == Alive {
+		state = Suspect
+	}
+	return state
+}
+
+// Members returns the view sorted by id, combining the gossiped heartbeats
+// with what our own failure detector has seen.
+func (members *membership) Members(detector *failureDetector) (list []Member) {
+	members.mutex.Lock()
+	defer members.mutex.Unlock()
+
+	for _, entry := range members.entries {
+		list = append(list, Member{
+			Info:     entry.info,
+			State:    members.state(entry, detector),
+			LastSeen: entry.updated,
+		})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Info.Id.Less(list[j].Info.Id)
+	})
+	return
+}
+
+func (members *membership) alive(detector *failureDetector) (alive []*ContactInfo) {
+	for _, member := range members.Members(detector) {
+		if member.State == Alive {
+			alive = append(alive, member.Info)
+		}
+	}
+	return
+}
+
+// Next returns the closest alive member following id.
+func (members *membership) Next(id NodeID, detector *failureDetector) (next *ContactInfo) {
+	for _, info := range members.alive(detector) {
+		if next == nil || id.Distance(info.Id).Cmp(id.Distance(next.Id)) < 0 {
+			next = info
+		}
+	}
+	return
+}
+
+func (members *membership) Random(detector *failureDetector, random func(n int) int) *ContactInfo {
+	alive := members.alive(detector)
+	if len(alive) == 0 {
+		return nil
+	}
+	return alive[random(len(alive))]
+}
+
+// GossipRound bumps our heartbeat, adds the nodes in our routing state to
+// the view and exchanges heartbeats with a random alive member.
+func (network *chordNetwork) GossipRound() (err error) {
+	network.members.Beat()
+	network.members.Observe(network.getPredecessor())
+	for _, succ := range network.successors.List() {
+		network.members.Observe(succ)
+	}
+	for _, finger := range network.fingerTable.Fingers() {
+		network.members.Observe(finger)
+	}
+
+	target := network.members.Random(network.detector, network.random)
+	if target == nil || !network.supports(target, CapGossip) {
+		return
+	}
+	return network.PushHeartbeats(target)
+}
+
+// PushHeartbeats exchanges heartbeats with info, outside of the gossip
+// rounds.
+func (network *chordNetwork) PushHeartbeats(info *ContactInfo) (err error) {
+	if !network.supports(info, CapGossip) {
+		return unsupported(info, "Gossip")
+	}
+	var heartbeats []Heartbeat
+	err = network.Call(info, func(client ChordClient) error {
+		heartbeats, err = client.Gossip(context.Background(), network.members.Heartbeats())
+		return err
+	})
+	if err == nil {
+		network.members.Merge(heartbeats)
+	}
+	return
+}
+
+func (peer *Peer) Gossip(ctx context.Context, heartbeats []Heartbeat) ([]Heartbeat, error) {
+	logger.Debug("Gossip: %d heartbeats", len(heartbeats))
+	peer.network.members.Merge(heartbeats)
+
+	return peer.network.members.Heartbeats(), nil
+}
+
+// Members returns this peer's approximate view of the whole ring.
+func (peer *Peer) Members() []Member {
+	return peer.network.members.Members(peer.network.detector)
+}
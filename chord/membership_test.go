@@ -0,0 +1,72 @@
+package chord
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeadMembersAreDropped(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	self := &ContactInfo{Address: "self", Id: NewNodeIDFromHash("self")}
+	other := &ContactInfo{Address: "other", Id: NewNodeIDFromHash("other")}
+	members := newMembership(self)
+	members.clock = clock
+	detector := newFailureDetector(DefaultFailureDetectorConfig)
+
+	members.Merge([]Heartbeat{{Info: other, Count: 3}})
+	if len(members.Heartbeats()) != 2 {
+		t.Fatalf("expected heartbeats for both nodes, got %v", members.Heartbeats())
+	}
+
+	clock.Advance(memberDeadTimeout)
+	if state := members.Members(detector)[0].State; state != Dead {
+		t.Fatalf("member is %v after %v without a heartbeat", state, memberDeadTimeout)
+	}
+	if heartbeats := members.Heartbeats(); len(heartbeats) != 1 {
+		t.Errorf("dead members are still gossiped: %v", heartbeats)
+	}
+
+	// A heartbeat that is no newer does not bring the tombstone back
+	members.Merge([]Heartbeat{{Info: other, Count: 3}})
+	if state := members.Members(detector)[0].State; state != Dead {
+		t.Errorf("a stale heartbeat made the member %v", state)
+	}
+
+	clock.Advance(memberTombstonePeriod)
+	members.Merge(nil)
+	if list := members.Members(detector); len(list) != 0 {
+		t.Errorf("tombstone is kept past its period: %v", list)
+	}
+}
+
+func TestDroppedMembersAreRemembered(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	self := &ContactInfo{Address: "self", Id: NewNodeIDFromHash("self")}
+	other := &ContactInfo{Address: "other", Id: NewNodeIDFromHash("other")}
+	members := newMembership(self)
+	members.clock = clock
+	detector := newFailureDetector(DefaultFailureDetectorConfig)
+
+	members.Merge([]Heartbeat{{Info: other, Count: 3}})
+	clock.Advance(memberDeadTimeout + memberTombstonePeriod)
+	members.Merge(nil)
+	if list := members.Members(detector); len(list) != 0 {
+		t.Fatalf("tombstone is kept past its period: %v", list)
+	}
+	if former := members.Former(); len(former) != 1 || !former[0].Id.Equals(other.Id) {
+		t.Fatalf("dropped member is not remembered: %v", former)
+	}
+
+	// Hearing from it again makes it a member rather than a former one
+	members.Merge([]Heartbeat{{Info: other, Count: 4}})
+	if former := members.Former(); len(former) != 0 {
+		t.Errorf("member is still listed as former: %v", former)
+	}
+
+	for i := 0; i < formerMemberLimit+1; i++ {
+		members.remember(&ContactInfo{Id: NewNodeIDFromHash(string(rune('a' + i)))})
+	}
+	if former := members.Former(); len(former) != formerMemberLimit {
+		t.Errorf("expected %d former members, got %d", formerMemberLimit, len(former))
+	}
+}
@@ -0,0 +1,196 @@
+package chord
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Metadata describes a node to the rest of the ring. It travels as JSON in
+// ContactInfo.Payload, so every RPC that passes the node on carries it.
+type Metadata struct {
+	Role    string `json:"role,omitempty"`
+	Zone    string `json:"zone,omitempty"`
+	Version string `json:"version,omitempty"`
+	// Endpoints are the services the node offers, by name.
+	Endpoints map[string]string `json:"endpoints,omitempty"`
+	// Labels are free-form attributes.
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// Encode returns the payload that carries the metadata.
+func (metadata Metadata) Encode() []byte {
+	payload, _ := json.Marshal(metadata)
+	return payload
+}
+
+// Attribute returns the value of one attribute: role, zone, version,
+// endpoint.<name> or label.<name>.
+func (metadata Metadata) Attribute(name string) (value string, ok bool) {
+	switch {
+	case name == "role":
+		return metadata.Role, metadata.Role != ""
+	case name == "zone":
+		return metadata.Zone, metadata.Zone != ""
+	case name == "version":
+		return metadata.Version, metadata.Version != ""
+	case strings.HasPrefix(name, "endpoint."):
+		value, ok = metadata.Endpoints[strings.TrimPrefix(name, "endpoint.")]
+	case strings.HasPrefix(name, "label."):
+		value, ok = metadata.Labels[strings.TrimPrefix(name, "label.")]
+	}
+	return
+}
+
+// Metadata decodes the metadata the node advertises. Nodes that advertise
+// none have empty metadata.
+func (info *ContactInfo) Metadata() (metadata Metadata, err error) {
+	if len(info.Payload) == 0 {
+		return
+	}
+	if err = json.Unmarshal(info.Payload, &metadata); err != nil {
+		err = invalidArgument("payload", "malformed metadata of %s: %v", info.Address, err)
+	}
+	return
+}
+
+// Query selects nodes by attribute, a node matches when it has every
+// attribute with the given value. An empty value matches any node that has
+// the attribute.
+type Query map[string]string
+
+func (query Query) Matches(info *ContactInfo) bool {
+	metadata, err := info.Metadata()
+	if err != nil {
+		return false
+	}
+	for name, want := range query {
+		value, ok := metadata.Attribute(name)
+		if !ok || (want != "" && value != want) {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseQuery reads a query from name=value terms.
+func ParseQuery(terms []string) (Query, error) {
+	query := Query{}
+	for _, term := range terms {
+		parts := strings.SplitN(term, "=", 2)
+		if parts[0] == "" {
+			return nil, invalidArgument("query", "malformed term %q", term)
+		}
+		if len(parts) == 1 {
+			parts = append(parts, "")
+		}
+		query[parts[0]] = parts[1]
+	}
+	return query, nil
+}
+
+// SetMetadata changes what the peer advertises. The new metadata is pushed
+// to the predecessor and successors right away, and spreads further with
+// gossip and stabilization.
+func (peer *Peer) SetMetadata(metadata Metadata) {
+	info := *peer.GetInfo()
+	info.Payload = metadata.Encode()
+	peer.setInfo(&info)
+	peer.network.members.Beat()
+
+	for _, neighbour := range peer.network.neighbourContacts() {
+		if err := peer.network.PushHeartbeats(neighbour); err != nil {
+			logger.Debug("failed to push metadata to %s: %v", neighbour.Address, err)
+		}
+	}
+	if succ := peer.GetSuccessor(); !succ.Id.Equals(info.Id) {
+		peer.network.Notify(succ)
+	}
+}
+
+// neighbourContacts returns the predecessor and the distinct successors.
+func (network *chordNetwork) neighbourContacts() (contacts []*ContactInfo) {
+	seen := map[string]bool{network.self().Id.String(): true}
+	for _, c := range append([]*ContactInfo{network.getPredecessor()}, network.successors.List()...) {
+		if c == nil || seen[c.Id.String()] {
+			continue
+		}
+		seen[c.Id.String()] = true
+		contacts = append(contacts, c)
+	}
+	return
+}
+
+// KnownNodes returns the nodes in the peer's own view of the ring that
+// match query, without asking anyone. The view is eventually complete, but
+// may miss nodes that joined recently.
+func (peer *Peer) KnownNodes(query Query) (nodes []*ContactInfo) {
+	if self := peer.GetInfo(); query.Matches(self) {
+		nodes = append(nodes, self)
+	}
+	for _, member := range peer.Members() {
+		if member.State == Alive && query.Matches(member.Info) {
+			nodes = append(nodes, member.Info)
+		}
+	}
+	return
+}
+
+// FindNodes asks every node in the ring whether it matches query, over the
+// broadcast tree.
+func (peer *Peer) FindNodes(ctx context.Context, query Query) ([]*ContactInfo, error) {
+	self := peer.GetInfo()
+	id := NewNodeIDFromHash(fmt.Sprintf("%s/find/%d/%d", self.Id.String(), peer.network.clock.Now().UnixNano(), peer.network.random(1<<30)))
+	return peer.HandleFindNodes(ctx, id.String(), self.Id, query)
+}
+
+// HandleFindNodes returns the nodes matching query in the arc from the peer
+// up to limit.
+func (peer *Peer) HandleFindNodes(ctx context.Context, id string, limit NodeID, query Query) (nodes []*ContactInfo, err error) {
+	logger.Debug("HandleFindNodes: %s", id)
+	if !peer.network.seen.Add(id) {
+		return
+	}
+	if self := peer.GetInfo(); query.Matches(self) {
+		nodes = append(nodes, self)
+	}
+
+	var mutex sync.Mutex
+	peer.network.fanOut(limit, CapDiscovery, func(child *ContactInfo, end NodeID) {
+		res, err := peer.network.FindNodes(ctx, child, id, end, query)
+		if err != nil {
+			logger.Warn("failed to search through %s: %v", child.Address, err)
+			return
+		}
+		mutex.Lock()
+		defer mutex.Unlock()
+		nodes = append(nodes, res...)
+	})
+	return
+}
+
+// FindRingNodes finds the nodes matching query in the ring the node at
+// address is part of, for tools outside the ring.
+func FindRingNodes(address string, query Query) ([]*ContactInfo, error) {
+	network := NewChordNetwork(&ContactInfo{Id: NewEmptyNodeID()})
+	entry, err := network.Ping(address)
+	if err != nil {
+		return nil, err
+	}
+
+	id := NewNodeIDFromHash(fmt.Sprintf("%s/find/%d", address, network.clock.Now().UnixNano()))
+	return network.FindNodes(context.Background(), entry, id.String(), entry.Id, query)
+}
+
+func (network *chordNetwork) FindNodes(ctx context.Context, info *ContactInfo, id string, limit NodeID, query Query) (res []*ContactInfo, err error) {
+	if !network.supports(info, CapDiscovery) {
+		return nil, unsupported(info, "FindNodes")
+	}
+	err = network.Call(info, func(client ChordClient) error {
+		res, err = client.FindNodes(ctx, id, limit, query)
+		return err
+	})
+	return
+}
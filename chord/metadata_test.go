@@ -0,0 +1,36 @@
+package chord_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lukaspj/go-chord/chord"
+)
+
+func TestMetadataIsVisibleOnOtherNodes(t *testing.T) {
+	ring := startRing(t, 5, nil)
+	defer ring.Stop()
+
+	tagged := ring.Peers[1]
+	tagged.SetMetadata(chord.Metadata{Role: "storage", Zone: "eu"})
+	query := chord.Query{"role": "storage", "zone": "eu"}
+
+	// The successor is pushed the new metadata before SetMetadata returns,
+	// the others learn it through gossip
+	successor := ring.Responsible(tagged.GetSuccessor().Id)
+	known := successor.KnownNodes(query)
+	if len(known) != 1 || !known[0].Id.Equals(tagged.GetInfo().Id) {
+		t.Errorf("KnownNodes on the successor returned %v, expected only %s", known, tagged.GetInfo().Address)
+	}
+
+	// Searching asks every node, so any of them finds it
+	for _, peer := range ring.Running() {
+		found, err := peer.FindNodes(context.Background(), query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(found) != 1 || !found[0].Id.Equals(tagged.GetInfo().Id) {
+			t.Errorf("FindNodes through %s returned %v, expected only %s", peer.GetInfo().Address, found, tagged.GetInfo().Address)
+		}
+	}
+}
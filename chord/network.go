@@ -2,65 +2,149 @@ package chord
 
 import (
 	"time"
-	"math/big"
-	"google.golang.org/grpc"
 	"context"
+	"math/rand"
+	"sync"
 )
 
 type chordNetwork struct {
 	fingerTable   fingerTable
 	successors    successorList
+	// mutex guards predecessor and lastDirtyTime, the successor list and
+	// the finger table lock themselves.
+	mutex         sync.RWMutex
 	predecessor   *ContactInfo
+	// localInfo is what we advertise about ourselves. It is replaced as a
+	// whole under infoMutex, never changed in place.
+	infoMutex     sync.RWMutex
 	localInfo     *ContactInfo
 	lastDirtyTime time.Time
+	// onAlive is called whenever failure detection hears back from a node.
+	onAlive       func(info *ContactInfo)
+	// onChanged is called whenever the routing state has changed.
+	onChanged     func()
+	rtt           *rttTable
+	detector      *failureDetector
+	members       *membership
+	// seeds are the addresses we connected through, kept to find our way
+	// back after a partition.
+	seeds         []string
+	transport     Transport
+	clock         Clock
+	// random returns a number in [0, n) for the protocol's random choices.
+	random        func(n int) int
+	// cache holds recent lookup results, nil unless lookup caching is enabled.
+	cache         *lookupCache
+	// proximityFingers picks the lowest latency node of each finger interval
+	// instead of its exact successor.
+	proximityFingers bool
+	// capabilities holds what nodes advertised when they were pinged.
+	capabilities  *capabilityTable
+	// enabled are the optional RPCs we call, a peer that advertises fewer
+	// calls only those, as an older one would.
+	enabled       Capability
+	// watchers are sent the predecessor and successor list as they change.
+	watchers      *watcherSet
+	// seen holds the broadcasts handled recently.
+	seen          *seenSet
+	// sequential runs what would be concurrent one piece at a time.
+	sequential    bool
 }
 
 func NewChordNetwork(info *ContactInfo) (network *chordNetwork) {
 	network = &chordNetwork{
-		fingerTable:   fingerTable{
-			fingers: [10]*ContactInfo{},
-			next:    0,
-		},
 		localInfo:     info,
+		rtt:           newRTTTable(),
+		detector:      newFailureDetector(DefaultFailureDetectorConfig),
+		members:       newMembership(info),
+		capabilities:  newCapabilityTable(),
+		enabled:       AllCapabilities,
+		watchers:      newWatcherSet(),
+		seen:          newSeenSet(),
+		transport:     grpcTransport{},
+		clock:         realClock{},
+		random:        rand.Intn,
 	}
 
-	for i := range network.successors {
-		network.successors.SetSuccessor(i, info)
+	for i := range network.successors.successors {
+		network.successors.successors[i] = info
 	}
 
 	return
 }
 
-func (network *chordNetwork) Call(contact *ContactInfo, cb func(client ChordClient) error) (err error) {
-	conn, err := grpc.Dial(contact.Address, grpc.WithInsecure())
-	defer conn.Close()
+// self returns what we advertise about ourselves.
+func (network *chordNetwork) self() *ContactInfo {
+	network.infoMutex.RLock()
+	defer network.infoMutex.RUnlock()
+	return network.localInfo
+}
+
+// getPredecessor returns our predecessor, nil if we do not know it.
+func (network *chordNetwork) getPredecessor() *ContactInfo {
+	network.mutex.RLock()
+	defer network.mutex.RUnlock()
+	return network.predecessor
+}
+
+func (network *chordNetwork) setPredecessor(info *ContactInfo) {
+	network.mutex.Lock()
+	defer network.mutex.Unlock()
+	network.predecessor = info
+}
 
+// setSelf replaces what we advertise about ourselves.
+func (network *chordNetwork) setSelf(info *ContactInfo) {
+	network.infoMutex.Lock()
+	network.localInfo = info
+	network.infoMutex.Unlock()
+	network.members.SetSelf(info)
+}
+
+func (network *chordNetwork) Call(contact *ContactInfo, cb func(client ChordClient) error) (err error) {
+	conn, err := network.transport.Dial(contact)
 	if err != nil {
 		logger.Error("error communicating with grpc server [%s]: %v", contact.Address, err)
-		return
+		network.cache.Forget(contact.Address)
+		return rpcError(contact, err)
 	}
+	defer conn.Close()
+
 	client := NewChordClient(conn)
-	err = cb(client)
+	start := network.clock.Now()
+	err = rpcError(contact, cb(client))
+	if err == nil {
+		network.rtt.Observe(contact.Address, network.clock.Now().Sub(start))
+	} else {
+		network.cache.Forget(contact.Address)
+	}
 
 	return
 }
 
 func (network *chordNetwork) Ping(address string) (info *ContactInfo, err error) {
-	info = &ContactInfo{
+	return network.PingNode(&ContactInfo{
 		Address: address,
 		Id:      NewEmptyNodeID(),
-	}
+	})
+}
 
-	network.Call(info, func(client ChordClient) error {
+// PingNode pings the given node, rather than whichever node answers on its
+// address, which matters when a host runs several virtual nodes.
+func (network *chordNetwork) PingNode(target *ContactInfo) (info *ContactInfo, err error) {
+	err = network.Call(target, func(client ChordClient) error {
 		info, err = client.Ping(context.Background())
 		return err
 	})
+	if err == nil {
+		network.capabilities.Learn(info)
+	}
 
 	return
 }
 
 func (network *chordNetwork) FindSuccessor(info *ContactInfo, id NodeID) (res *ContactInfo, err error) {
-	network.Call(info, func(client ChordClient) error {
+	err = network.Call(info, func(client ChordClient) error {
 		res, err = client.FindSuccessor(context.Background(), id)
 		return err
 	})
@@ -69,7 +153,7 @@ func (network *chordNetwork) FindSuccessor(info *ContactInfo, id NodeID) (res *C
 }
 
 func (network *chordNetwork) ClosestPrecedingNode(info *ContactInfo, id NodeID) (res *ContactInfo, err error) {
-	network.Call(info, func(client ChordClient) error {
+	err = network.Call(info, func(client ChordClient) error {
 		res, err = client.ClosestPrecedingNode(context.Background(), id)
 		return err
 	})
@@ -77,15 +161,20 @@ func (network *chordNetwork) ClosestPrecedingNode(info *ContactInfo, id NodeID)
 }
 
 func (network *chordNetwork) Predecessor(info *ContactInfo) (res *ContactInfo, err error) {
-	network.Call(info, func(client ChordClient) error {
-		res, err = client.Predecessor(context.Background())
+	explicit := network.supports(info, CapExplicitPredecessor)
+	err = network.Call(info, func(client ChordClient) error {
+		if explicit {
+			res, err = client.Predecessor(context.Background())
+		} else {
+			res, err = client.LegacyPredecessor(context.Background())
+		}
 		return err
 	})
 	return
 }
 
 func (network *chordNetwork) Successor(info *ContactInfo) (res *ContactInfo, err error) {
-	network.Call(info, func(client ChordClient) error {
+	err = network.Call(info, func(client ChordClient) error {
 		res, err = client.Successor(context.Background())
 		return err
 	})
@@ -93,8 +182,92 @@ func (network *chordNetwork) Successor(info *ContactInfo) (res *ContactInfo, err
 }
 
 func (network *chordNetwork) Notify(info *ContactInfo) (err error) {
-	network.Call(info, func(client ChordClient) error {
-		err = client.Notify(context.Background(), network.localInfo)
+	err = network.Call(info, func(client ChordClient) error {
+		err = client.Notify(context.Background(), network.self())
+		return err
+	})
+	return
+}
+
+func (network *chordNetwork) SuccessorList(info *ContactInfo) (res []*ContactInfo, err error) {
+	if !network.supports(info, CapSuccessorList) {
+		return nil, unsupported(info, "SuccessorList")
+	}
+	err = network.Call(info, func(client ChordClient) error {
+		res, err = client.SuccessorList(context.Background())
+		return err
+	})
+	if err == nil {
+		network.cache.AddSuccessors(info, res)
+	}
+	return
+}
+
+func (network *chordNetwork) Store(info *ContactInfo, item *Item) (err error) {
+	if !network.supports(info, CapStorage) {
+		return unsupported(info, "Store")
+	}
+	err = network.Call(info, func(client ChordClient) error {
+		err = client.Store(context.Background(), item)
+		return err
+	})
+	return
+}
+
+func (network *chordNetwork) Fetch(info *ContactInfo, key string) (res *Item, err error) {
+	if !network.supports(info, CapStorage) {
+		return nil, unsupported(info, "Fetch")
+	}
+	err = network.Call(info, func(client ChordClient) error {
+		res, err = client.Fetch(context.Background(), key)
+		return err
+	})
+	return
+}
+
+func (network *chordNetwork) StoreHint(info *ContactInfo, target *ContactInfo, item *Item) (err error) {
+	if !network.supports(info, CapStorage) {
+		return unsupported(info, "StoreHint")
+	}
+	err = network.Call(info, func(client ChordClient) error {
+		err = client.StoreHint(context.Background(), target, item)
+		return err
+	})
+	return
+}
+
+func (network *chordNetwork) Load(info *ContactInfo) (res *LoadReport, err error) {
+	if !network.supports(info, CapLoad) {
+		return nil, unsupported(info, "Load")
+	}
+	err = network.Call(info, func(client ChordClient) error {
+		res, err = client.Load(context.Background())
+		return err
+	})
+	return
+}
+
+func (network *chordNetwork) FindSuccessors(info *ContactInfo, ids []NodeID) (res []*ContactInfo, err error) {
+	if !network.supports(info, CapBatchLookup) {
+		return nil, unsupported(info, "FindSuccessors")
+	}
+	err = network.Call(info, func(client ChordClient) error {
+		res, err = client.FindSuccessors(context.Background(), ids)
+		return err
+	})
+	return
+}
+
+func (network *chordNetwork) Neighbours(info *ContactInfo, notify bool) (res Neighbours, err error) {
+	if !network.supports(info, CapNeighbours) {
+		return Neighbours{}, unsupported(info, "Neighbours")
+	}
+	var sender *ContactInfo
+	if notify {
+		sender = network.self()
+	}
+	err = network.Call(info, func(client ChordClient) error {
+		res, err = client.Neighbours(context.Background(), sender)
 		return err
 	})
 	return
@@ -103,48 +276,94 @@ func (network *chordNetwork) Notify(info *ContactInfo) (err error) {
 func (network *chordNetwork) Stabilize() (err error) {
 	var x *ContactInfo
 
+	successor := network.successors.GetSuccessor(0)
+	if !successor.Id.Equals(network.self().Id) && network.supports(successor, CapNeighbours) {
+		var neighbours Neighbours
+		if neighbours, err = network.Neighbours(successor, true); err == nil {
+			network.detector.Alive(successor.Id)
+			network.alive(neighbours.Node)
+			network.adoptNeighbours(neighbours)
+			return
+		}
+		logger.Warn("successor %s did not answer, stabilizing the long way: %v", successor.Address, err)
+	}
+
 	network.UpdateSuccessorList()
+	network.cache.AddSuccessors(network.self(), network.successors.List())
 
-	successor := network.successors.GetSuccessor(0)
+	successor = network.successors.GetSuccessor(0)
 	x, err = network.Predecessor(successor)
 
 	if err != nil {
 		logger.Error("an error happened during stabilize: %v", err)
 	}
 
-	if x != nil && x.Id.Equals(network.localInfo.Id) {
+	if x != nil && x.Id.Equals(network.self().Id) {
 		// Nothing has changed
 		return
 	}
 
-	if x != nil && x.Id.Between(network.localInfo.Id, successor.Id) {
+	if x != nil && x.Id.Between(network.self().Id, successor.Id) {
 		if network.successors.SetSuccessor(0, x) {
-			network.lastDirtyTime = time.Now()
+			network.changed()
 		}
 	}
 	network.Notify(successor)
 	return
 }
 
+// adoptNeighbours finishes a stabilization round from the successor's reply
+// to Neighbours, which already carries our notification. The successor's
+// own list, shifted by one, becomes ours, and its predecessor becomes our
+// successor if it lies between us.
+func (network *chordNetwork) adoptNeighbours(neighbours Neighbours) {
+	var list []*ContactInfo
+	x := neighbours.Predecessor
+	if x != nil && !x.Id.Equals(network.self().Id) && x.Id.Between(network.self().Id, neighbours.Node.Id) {
+		list = append(list, x)
+	}
+	list = append(list, neighbours.Node)
+	list = append(list, neighbours.Successors...)
+
+	dirty := false
+	for j := 0; j < successorListSize; j++ {
+		curr := list[len(list)-1]
+		if j < len(list) {
+			curr = list[j]
+		}
+		dirty = network.successors.SetSuccessor(j, curr) || dirty
+	}
+	network.cache.AddSuccessors(network.self(), network.successors.List())
+
+	if dirty {
+		network.changed()
+	}
+}
+
 func (network *chordNetwork) UpdateSuccessorList() {
 	var err error
-	for i, succ := range network.successors {
+	for _, succ := range network.successors.List() {
 		if succ == nil || succ.Id.IsZero() {
 			continue
 		}
 
-		succ, err = network.Ping(succ.Address)
-		if err != nil {
+		var state NodeState
+		succ, state = network.Probe(succ)
+		if state == Dead {
 			logger.Error("unresponsive successor, trying to rebuild successorlist from the next successor")
 			continue
 		}
+		if state == Suspect {
+			logger.Warn("successor is suspected to have failed, keeping the successorlist until it is confirmed")
+			return
+		}
 
 		// Found a stable successor, build list
 		dirty := false
 		dirty = network.successors.SetSuccessor(0, succ) || dirty
 
 		var prev, curr *ContactInfo
-		for j := i + 1; j < len(network.successors); j++ {
+		for j := 1; j < successorListSize; j++ {
 			prev = network.successors.GetSuccessor(j - 1)
 			curr, err = network.Successor(prev)
 			if err != nil {
@@ -155,51 +374,146 @@ func (network *chordNetwork) UpdateSuccessorList() {
 		}
 
 		if dirty {
-			network.lastDirtyTime = time.Now()
+			network.changed()
+		}
+		return
+	}
+
+	// Every successor has failed, fall back to the closest member we know of
+	if next := network.members.Next(network.self().Id, network.detector); next != nil {
+		logger.Warn("all successors have failed, falling back to member %s", next.Address)
+		if network.successors.SetSuccessor(0, next) {
+			network.changed()
 		}
-		break
 	}
 }
 
 func (network *chordNetwork) FixFingers() (err error) {
-	network.fingerTable.next++
-	if network.fingerTable.next >= fingerCount {
-		network.fingerTable.next = 0
-	}
-	fingerId := NewEmptyNodeID()
-
-	// TODO move to NodeID file
-	// fingerId.Val = peer.Info.Id.Val + 2^(next-1) mod 2^(20*8)
-	var a, b, e big.Int
-	tmp := network.localInfo.Id.BigInt()
-	a.Add(tmp,
-		e.Lsh(big.NewInt(2), uint(network.fingerTable.next)))
-	b.Exp(big.NewInt(2), big.NewInt(20*8), nil)
-
-	fingerId.Val = tmp.Mod(&a, &b).Bytes()
+	next := network.fingerTable.Advance()
+	self := network.self().Id
 
 	var successor *ContactInfo
-	successor, err = network.FindSuccessor(network.localInfo, fingerId)
+	if successor, err = network.FindSuccessor(network.self(), self.FingerStart(next)); err != nil {
+		return
+	}
 
-	if err == nil {
-		if network.fingerTable.SetFinger(network.fingerTable.next, successor) {
-			network.lastDirtyTime = time.Now()
+	// The fingers after it that start before the successor point to it as
+	// well, so a ring of N nodes needs about log N lookups rather than one
+	// per finger
+	dirty := false
+	for index := next; index < fingerCount && (index == next || self.FingerStart(index).Between(self, successor.Id)); index++ {
+		finger := successor
+		if network.proximityFingers {
+			finger = network.closestCandidate(index, successor)
 		}
+		dirty = network.fingerTable.SetFinger(index, finger) || dirty
+		network.fingerTable.SetNext(index)
+	}
+	if dirty {
+		network.changed()
 	}
 	return
 }
 
+// closestCandidate collects the nodes in the finger's interval from the
+// successor list of its exact successor, and returns the one with the lowest
+// round-trip time. Any node in the interval is a correct finger. When the
+// exact successor is already past the interval, it is the only choice.
+func (network *chordNetwork) closestCandidate(index int, successor *ContactInfo) *ContactInfo {
+	start := network.self().Id.FingerStart(index)
+	end := network.self().Id
+	if index+1 < fingerCount {
+		end = network.self().Id.FingerStart(index + 1)
+	}
+	if !successor.Id.Equals(start) && (!successor.Id.Between(start, end) || successor.Id.Equals(end)) {
+		network.fingerTable.SetCandidates(index, nil)
+		return successor
+	}
+
+	candidates := []*ContactInfo{successor}
+	if list, err := network.SuccessorList(successor); err == nil {
+		for _, c := range list {
+			if c.Id.Between(successor.Id, end) && !c.Id.Equals(end) && !c.Id.Equals(successor.Id) {
+				candidates = append(candidates, c)
+			}
+		}
+	}
+	network.fingerTable.SetCandidates(index, candidates)
+
+	best := successor
+	bestRTT := time.Duration(-1)
+	for _, c := range candidates {
+		rtt, ok := network.rtt.Get(c.Address)
+		if !ok {
+			if _, err := network.PingNode(c); err != nil {
+				continue
+			}
+			rtt, _ = network.rtt.Get(c.Address)
+		}
+		if bestRTT < 0 || rtt < bestRTT {
+			best, bestRTT = c, rtt
+		}
+	}
+	return best
+}
+
 func (network *chordNetwork) CheckPredecessor() (err error) {
-	if network.predecessor != nil {
-		if _, err = network.Ping(network.predecessor.Address); err != nil {
+	if pred := network.getPredecessor(); pred != nil {
+		if _, state := network.Probe(pred); state == Dead {
 			logger.Warn("Connection to predecessor has been lost")
-			network.predecessor = nil
-			network.lastDirtyTime = time.Now()
+			network.setPredecessor(nil)
+			network.changed()
 		}
 	}
 	return
 }
 
+// async runs fn on a goroutine of its own, or right away on a sequential
+// network.
+func (network *chordNetwork) async(fn func()) {
+	if network.sequential {
+		fn()
+		return
+	}
+	go fn()
+}
+
+func (network *chordNetwork) alive(info *ContactInfo) {
+	if network.onAlive != nil && info != nil {
+		network.onAlive(info)
+	}
+}
+
+// changed records that the routing state has changed, which resets the
+// maintenance back-off, drops cached lookups and tells watchers.
+func (network *chordNetwork) changed() {
+	network.mutex.Lock()
+	network.lastDirtyTime = network.clock.Now()
+	network.mutex.Unlock()
+	network.cache.Clear()
+	network.watchers.Publish(network.neighbours())
+	if network.onChanged != nil {
+		network.onChanged()
+	}
+}
+
+// SetClock makes the network and its failure detection use clock.
+func (network *chordNetwork) SetClock(clock Clock) {
+	network.clock = clock
+	network.detector.clock = clock
+	network.members.clock = clock
+	network.seen.clock = clock
+}
+
 func (network *chordNetwork) TimeSinceChange() time.Duration {
-	return time.Now().Sub(network.lastDirtyTime)
+	network.mutex.RLock()
+	defer network.mutex.RUnlock()
+	return network.clock.Now().Sub(network.lastDirtyTime)
+}
+
+// backoff eases the interval of a maintenance function from start to end
+// over the gear-down period after the routing state last changed.
+func (network *chordNetwork) backoff(start, end time.Duration) time.Duration {
+	interpolate := cubic(float64(start), float64(end))
+	return time.Duration(interpolate(float64(network.TimeSinceChange()) / float64(gearDownPeriod)))
 }
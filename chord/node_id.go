@@ -7,13 +7,19 @@ import (
 	"math/big"
 )
 
-const IdLength = 20
+// IdLength is the length in bytes of the ids NewNodeIDFromHash makes, which
+// sets the size of the identifier circle.
+const IdLength = sha256.Size
 
 type NodeID struct {
 	Val []byte `json:"val"`
 }
 
 func NewNodeIDFromString(id string) (ret NodeID) {
+	// String drops leading zeros, which can leave an odd number of digits
+	if len(id)%2 == 1 {
+		id = "0" + id
+	}
 	decoded, err := hex.DecodeString(id)
 
 	if err != nil {
@@ -88,6 +94,48 @@ func (node NodeID) IsZero() bool {
 func (node NodeID) Between(a, b NodeID) bool {
 	return a.Equals(b) || // Handle full-circle case
 		node.Equals(b) || // Equality, handle b]
-		(a.Less(b) && !node.Less(a) && node.Less(b)) || // Trivially between a and b
-		(b.Less(a) && !node.Less(a) || node.Less(b)) // Handle wrap-around case
+		(a.Less(b) && a.Less(node) && node.Less(b)) || // Trivially between a and b
+		(b.Less(a) && (a.Less(node) || node.Less(b))) // Handle wrap-around case
+}
+
+// ringModulus returns the size of the identifier circle, 2^(8*IdLength). It
+// does not depend on the length of any id, an id with leading zero bytes is
+// often shorter once it has been through big.Int or hex.
+func ringModulus() *big.Int {
+	return big.NewInt(0).Lsh(big.NewInt(1), uint(IdLength*8))
+}
+
+// Distance returns the clockwise distance from node to other.
+func (node NodeID) Distance(other NodeID) *big.Int {
+	modulus := ringModulus()
+	diff := big.NewInt(0).Sub(other.BigInt(), node.BigInt())
+	return diff.Mod(diff, modulus)
+}
+
+// ArcFraction returns the part of the circle covered by (node, other].
+func (node NodeID) ArcFraction(other NodeID) float64 {
+	if node.Equals(other) {
+		return 1
+	}
+	fraction, _ := big.NewFloat(0).Quo(
+		big.NewFloat(0).SetInt(node.Distance(other)),
+		big.NewFloat(0).SetInt(ringModulus())).Float64()
+	return fraction
+}
+
+// Midpoint returns the id halfway along the arc from node to other.
+func (node NodeID) Midpoint(other NodeID) (ret NodeID) {
+	modulus := ringModulus()
+	mid := big.NewInt(0).Rsh(node.Distance(other), 1)
+	mid.Add(mid, node.BigInt()).Mod(mid, modulus)
+	ret.Val = mid.Bytes()
+	return
+}
+
+// FingerStart returns the id the finger at index must succeed, node + 2^index.
+func (node NodeID) FingerStart(index int) (ret NodeID) {
+	start := big.NewInt(0).Lsh(big.NewInt(1), uint(index))
+	start.Add(start, node.BigInt()).Mod(start, ringModulus())
+	ret.Val = start.Bytes()
+	return
 }
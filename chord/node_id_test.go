@@ -0,0 +1,65 @@
+package chord
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+// power returns the id 2^exponent, as short as big.Int makes it.
+func power(exponent uint) NodeID {
+	return NodeID{Val: big.NewInt(0).Lsh(big.NewInt(1), exponent).Bytes()}
+}
+
+func TestShortIdsLiveOnTheWholeRing(t *testing.T) {
+	// The hash of "286" starts with a zero byte, and loses it on the wire
+	hashed := NewNodeIDFromHash("286")
+	short := NewNodeIDFromString(hashed.String())
+	if len(short.Val) >= IdLength {
+		t.Fatalf("%s is %d bytes long, expected a leading zero byte to be dropped", short.String(), len(short.Val))
+	}
+	if !short.Equals(hashed) || short.Distance(hashed).Sign() != 0 || hashed.Distance(short).Sign() != 0 {
+		t.Errorf("%s and its shortened form are apart", hashed.String())
+	}
+
+	// (2^247, 2^246] runs almost all the way around
+	expected := 1 - math.Pow(2, -10)
+	if fraction := power(247).ArcFraction(power(246)); math.Abs(fraction-expected) > 1e-9 {
+		t.Errorf("arc from 2^247 to 2^246 covers %v of the ring, expected %v", fraction, expected)
+	}
+
+	// Its midpoint is past the top of the ring, not wrapped at 2^248
+	mid := big.NewInt(0).Lsh(big.NewInt(1), 8*IdLength)
+	mid.Add(mid, power(246).BigInt()).Add(mid, power(247).BigInt()).Rsh(mid, 1)
+	if got := power(247).Midpoint(power(246)); got.BigInt().Cmp(mid) != 0 {
+		t.Errorf("midpoint from 2^247 to 2^246 is %s, expected %s", got.String(), mid.Text(16))
+	}
+
+	full := NodeID{Val: append(make([]byte, IdLength-len(short.Val)), short.Val...)}
+	if d := short.Distance(power(255)); d.Cmp(full.Distance(power(255))) != 0 {
+		t.Errorf("distance from a short id is %s, from its full form %s", d.Text(16), full.Distance(power(255)).Text(16))
+	}
+}
+
+func TestFingerStartOfShortId(t *testing.T) {
+	id := power(247)
+	tests := []struct {
+		index    int
+		expected *big.Int
+	}{
+		{0, big.NewInt(0).Add(id.BigInt(), big.NewInt(1))},
+		{250, big.NewInt(0).Add(id.BigInt(), power(250).BigInt())},
+		{fingerCount - 1, big.NewInt(0).Add(id.BigInt(), power(255).BigInt())},
+	}
+	for _, test := range tests {
+		if start := id.FingerStart(test.index); start.BigInt().Cmp(test.expected) != 0 {
+			t.Errorf("finger %d of 2^247 starts at %s, expected %s", test.index, start.String(), test.expected.Text(16))
+		}
+	}
+
+	// The top finger wraps past zero
+	top := NodeID{Val: big.NewInt(0).Add(power(255).BigInt(), big.NewInt(5)).Bytes()}
+	if start := top.FingerStart(fingerCount - 1); start.BigInt().Cmp(big.NewInt(5)) != 0 {
+		t.Errorf("top finger of %s starts at %s, expected 5", top.String(), start.String())
+	}
+}
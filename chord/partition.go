@@ -0,0 +1,66 @@
+package chord
+
+import (
+	"time"
+)
+
+const partitionCheckInterval = 30 * time.Second
+
+// CheckPartition probes one node we used to know, a dead member, a former
+// member dropped from the view or a seed we connected through, and if it
+// answers checks whether it still routes to us. A split network leaves two
+// rings that stabilize on their own, and this is how they find each other
+// again.
+func (peer *Peer) CheckPartition() (err error) {
+	var candidates []*ContactInfo
+	for _, member := range peer.Members() {
+		if member.State == Dead {
+			candidates = append(candidates, member.Info)
+		}
+	}
+	candidates = append(candidates, peer.network.members.Former()...)
+	for _, seed := range peer.network.seeds {
+		candidates = append(candidates, &ContactInfo{Address: seed, Id: NewEmptyNodeID()})
+	}
+	if len(candidates) == 0 {
+		return
+	}
+
+	candidate := candidates[peer.network.random(len(candidates))]
+	var contact *ContactInfo
+	if contact, err = peer.network.PingNode(candidate); err != nil {
+		// Still unreachable, nothing to merge with
+		return nil
+	}
+	peer.network.detector.Alive(contact.Id)
+
+	return peer.network.MergeWith(contact)
+}
+
+// MergeWith looks our own id up through contact. In a single ring that
+// lookup ends at us; if it ends elsewhere, contact's ring does not know us,
+// so we take the node it found as successor when it is closer than ours and
+// notify it, which lets stabilization on both sides splice the rings
+// together.
+func (network *chordNetwork) MergeWith(contact *ContactInfo) (err error) {
+	var found *ContactInfo
+	if found, err = network.FindSuccessor(contact, network.self().Id); err != nil || found == nil {
+		return
+	}
+	if found.Id.Equals(network.self().Id) {
+		return
+	}
+
+	logger.Warn("%s routes our id to %s, merging with its ring", contact.Address, found.Address)
+	network.members.Observe(contact)
+	network.members.Observe(found)
+
+	successor := network.successors.GetSuccessor(0)
+	if successor.Id.Equals(network.self().Id) || found.Id.Between(network.self().Id, successor.Id) {
+		if network.successors.SetSuccessor(0, found) {
+			network.changed()
+		}
+	}
+
+	return network.Notify(found)
+}
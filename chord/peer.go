@@ -16,19 +16,70 @@ const fixFingersIntervalStart = time.Second
 const fixFingersIntervalEnd = 5 * time.Minute
 
 type Peer struct {
-	Info                     *ContactInfo
-	Port                     int
-	network                  *chordNetwork
-	stabilizationFunction    tickingFunction
-	fixFingersFunction       tickingFunction
-	checkPredecessorFunction tickingFunction
+	Port             int
+	Quorum           Quorum
+	LoadBalancing    bool
+	ProximityFingers bool
+	LookupCache      bool
+	FailureDetector  FailureDetectorConfig
+	// Transport, Clock and Random replace the network, the time and the
+	// randomness the protocol uses, for example to run it in a simulator.
+	// They are left as nil to use the real ones.
+	Transport Transport
+	Clock     Clock
+	Random    func(n int) int
+	// Sequential makes the peer do the work it would spread over
+	// goroutines one piece at a time and in a fixed order, so that a
+	// simulator can replay a run exactly.
+	Sequential bool
+	// Faults, if set before the peer starts serving, are injected into
+	// the RPCs it makes and answers.
+	Faults    *Faults
+	// Version and Capabilities are advertised to other peers, lowering them
+	// makes the peer behave like an older one.
+	Version      uint32
+	Capabilities Capability
+	// Handler handles the messages routed to keys the peer is responsible
+	// for.
+	Handler   MessageHandler
+	// BroadcastHandler handles the broadcasts that reach the peer.
+	BroadcastHandler BroadcastHandler
+	network   *chordNetwork
+	store     *dataStore
+	hints     *hintStore
+	load      *loadTracker
+	topics    *topicTable
+	tickers   map[string]tickingFunction
+	server    *grpc.Server
+	// unfollow stops watching the successor.
+	unfollow  context.CancelFunc
+}
+
+// MaintenanceTask is one of the periodic functions that keep a peer's state
+// up to date. Run performs a single round and returns the number of
+// nanoseconds until the next one.
+type MaintenanceTask struct {
+	Name string
+	Run  func() int
 }
 
 func NewPeer(info *ContactInfo, port int) (peer Peer) {
 	logger.Info("Creating new peer, with id: %s", info.Id.String())
 	peer.Port = port
-	peer.Info = info
-	peer.network = NewChordNetwork(peer.Info)
+	peer.Quorum = DefaultQuorum
+	peer.Version = ProtocolVersion
+	peer.Capabilities = AllCapabilities
+	peer.FailureDetector = DefaultFailureDetectorConfig
+	peer.network = NewChordNetwork(info)
+	peer.store = newDataStore()
+	peer.hints = newHintStore()
+	peer.load = newLoadTracker()
+	peer.topics = newTopicTable()
+
+	hints, network := peer.hints, peer.network
+	network.onAlive = func(info *ContactInfo) {
+		network.async(func() { hints.HandOff(network, info) })
+	}
 
 	return
 }
@@ -36,58 +87,176 @@ func NewPeer(info *ContactInfo, port int) (peer Peer) {
 func (peer *Peer) Listen() {
 	logger.Info("Listening on port: %d", peer.Port)
 
-	peer.network.predecessor = nil
-	if peer.network.successors.SetSuccessor(0, peer.Info) {
-		peer.network.lastDirtyTime = time.Now()
+	if l, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", peer.Port)); err == nil {
+		peer.Serve(l)
+	} else {
+		logger.Error("failed to listen on port %d: %v", peer.Port, err)
 	}
 
-	grpcServer := grpc.NewServer()
-	api.RegisterChordServer(grpcServer, &ServiceWrapper{service: peer})
+	peer.Start()
+}
 
-	if l, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", peer.Port)); err == nil {
-		go grpcServer.Serve(l)
+// Serve answers RPCs for this peer on l in the background.
+func (peer *Peer) Serve(l net.Listener) {
+	advertised := func(context.Context) Capability { return peer.GetInfo().Capabilities }
+	peer.server = grpc.NewServer(serverOptions(peer.Faults, advertised)...)
+	api.RegisterChordServer(peer.server, &ServiceWrapper{service: peer})
+
+	go peer.server.Serve(l)
+}
+
+// Start resets the routing state and starts the maintenance functions. It is
+// called by Listen, or by a Host serving the peer as a virtual node.
+func (peer *Peer) Start() {
+	peer.Reset()
+
+	peer.tickers = make(map[string]tickingFunction)
+	for _, task := range peer.MaintenanceTasks() {
+		peer.tickers[task.Name] = StartTickingFunctionWithClock(peer.network.clock, task.Run)
+	}
+	tickers := peer.tickers
+	peer.network.onChanged = func() {
+		// Topic trees follow the routing state
+		if tf, ok := tickers["refresh-topics"]; ok {
+			tf.Nudge()
+		}
+	}
+
+	var ctx context.Context
+	ctx, peer.unfollow = context.WithCancel(context.Background())
+	go peer.followSuccessor(ctx)
+}
+
+// Stop stops the maintenance functions and, if the peer serves its own
+// RPCs, the server. The peer simply disappears from the ring, as if it had
+// crashed.
+func (peer *Peer) Stop() {
+	for _, tf := range peer.tickers {
+		tf.Stop()
+	}
+	if peer.unfollow != nil {
+		peer.unfollow()
+	}
+	if peer.server != nil {
+		peer.server.Stop()
+	}
+}
+
+// Reset applies the peer's settings and leaves it in a ring of its own,
+// without starting any maintenance. A simulator that runs the maintenance
+// tasks itself calls it instead of Start.
+func (peer *Peer) Reset() {
+	info := *peer.GetInfo()
+	info.Version = peer.Version
+	info.Capabilities = peer.Capabilities
+	peer.setInfo(&info)
+	peer.network.enabled = peer.Capabilities
+	peer.network.proximityFingers = peer.ProximityFingers
+	peer.network.sequential = peer.Sequential
+	peer.network.detector.config = peer.FailureDetector
+	if peer.LookupCache {
+		peer.network.cache = newLookupCache(lookupCacheSize)
+	}
+	switch {
+	case peer.Transport != nil:
+		peer.network.transport = peer.Transport
+	case peer.Faults != nil:
+		peer.network.transport = grpcTransport{faults: peer.Faults, from: peer.GetInfo().Address}
+	}
+	if peer.Clock != nil {
+		peer.network.SetClock(peer.Clock)
+		peer.load.clock = peer.Clock
+		peer.load.windowStart = peer.Clock.Now()
+		peer.topics.clock = peer.Clock
+	}
+	if peer.Random != nil {
+		peer.network.random = peer.Random
 	}
 
-	peer.stabilizationFunction = StartTickingFunction(func() int {
-		interpolate := cubic(float64(stabilizationIntervalStart), float64(stabilizationIntervalEnd))
+	peer.network.setPredecessor(nil)
+	if peer.network.successors.SetSuccessor(0, peer.GetInfo()) {
+		peer.network.changed()
+	}
+}
+
+// MaintenanceTasks returns the periodic functions Start runs for this peer.
+func (peer *Peer) MaintenanceTasks() (tasks []MaintenanceTask) {
+	tasks = append(tasks, MaintenanceTask{Name: "stabilize", Run: func() int {
 		err := peer.network.Stabilize()
 		if err != nil {
 			logger.Error("error when stabilizing: %v", err)
 		}
-		return int(interpolate(float64(peer.network.TimeSinceChange()) / float64(gearDownPeriod)))
-	})
+		return int(peer.network.backoff(stabilizationIntervalStart, stabilizationIntervalEnd))
+	}})
 
-	peer.fixFingersFunction = StartTickingFunction(func() int {
-		interpolate := cubic(float64(fixFingersIntervalStart), float64(fixFingersIntervalEnd))
+	tasks = append(tasks, MaintenanceTask{Name: "fix-fingers", Run: func() int {
 		err := peer.network.FixFingers()
 		if err != nil {
 			logger.Error("error when fixing fingers: %v", err)
 		}
-		return int(interpolate(float64(peer.network.TimeSinceChange()) / float64(gearDownPeriod)))
-	})
+		return int(peer.network.backoff(fixFingersIntervalStart, fixFingersIntervalEnd))
+	}})
 
-	peer.checkPredecessorFunction = StartTickingFunction(func() int {
+	tasks = append(tasks, MaintenanceTask{Name: "check-predecessor", Run: func() int {
 		err := peer.network.CheckPredecessor()
 		if err != nil {
 			logger.Error("error when checking predecessor: %v", err)
 		}
+		peer.ReplayHints()
 		return int(time.Second * 20)
-	})
+	}})
+
+	tasks = append(tasks, MaintenanceTask{Name: "gossip", Run: func() int {
+		err := peer.network.GossipRound()
+		if err != nil {
+			logger.Error("error when gossiping: %v", err)
+		}
+		return int(gossipInterval)
+	}})
+
+	tasks = append(tasks, MaintenanceTask{Name: "check-partition", Run: func() int {
+		err := peer.CheckPartition()
+		if err != nil {
+			logger.Error("error when checking for partitions: %v", err)
+		}
+		return int(partitionCheckInterval)
+	}})
+
+	tasks = append(tasks, MaintenanceTask{Name: "refresh-topics", Run: func() int {
+		err := peer.RefreshTopics()
+		if err != nil {
+			logger.Error("error when refreshing topics: %v", err)
+		}
+		return int(peer.network.backoff(time.Second, topicRefreshInterval))
+	}})
+
+	if peer.LoadBalancing {
+		tasks = append(tasks, MaintenanceTask{Name: "balance-load", Run: func() int {
+			err := peer.BalanceLoad()
+			if err != nil {
+				logger.Error("error when balancing load: %v", err)
+			}
+			return int(loadBalancingInterval)
+		}})
+	}
+
+	return
 }
 
 func (peer *Peer) Connect(address string) (err error) {
 	var info *ContactInfo
 	logger.Info("Connecting to: %s", address)
+	peer.network.seeds = append(peer.network.seeds, address)
 	if info, err = peer.network.Ping(address); err == nil {
 		logger.Info("Connection successful, remote peer is: %s", info.Id.String())
 		var successor *ContactInfo
-		logger.Info("Looking up successor to: %s", peer.Info.Id.String())
-		successor, err = peer.network.FindSuccessor(info, peer.Info.Id)
+		logger.Info("Looking up successor to: %s", peer.GetInfo().Id.String())
+		successor, err = peer.network.FindSuccessor(info, peer.GetInfo().Id)
 		if err != nil {
 			logger.Error("Failed to lookup successor: %v", err)
 		}
 		if peer.network.successors.SetSuccessor(0, successor) {
-			peer.network.lastDirtyTime = time.Now()
+			peer.network.changed()
 		}
 	} else {
 		logger.Error("Failed to connect: %v", err)
@@ -100,21 +269,29 @@ func (peer *Peer) GetSuccessor() (info *ContactInfo) {
 }
 
 func (peer *Peer) GetPredecessor() (info *ContactInfo) {
-	return peer.network.predecessor
+	return peer.network.getPredecessor()
+}
+
+// GetInfo returns what the peer currently advertises about itself.
+func (peer *Peer) GetInfo() *ContactInfo {
+	return peer.network.self()
 }
 
 func (peer *Peer) ResponsibleFor(id NodeID) bool {
-	return id.Between(peer.network.predecessor.Id, peer.Info.Id)
+	return id.Between(peer.GetPredecessor().Id, peer.GetInfo().Id)
 }
 
 func (peer *Peer) Poke() {
-	go func() { peer.stabilizationFunction.tick <- true }()
-	go func() { peer.fixFingersFunction.tick <- true }()
+	for _, name := range []string{"stabilize", "fix-fingers"} {
+		if tf, ok := peer.tickers[name]; ok {
+			go tf.Tick()
+		}
+	}
 }
 
 func (peer *Peer) Ping(ctx context.Context) (info *ContactInfo, err error) {
 	logger.Debug("Ping")
-	info = peer.Info
+	info = peer.GetInfo()
 	if info == nil {
 		logger.Error("Error")
 	}
@@ -127,10 +304,13 @@ func (peer *Peer) FindSuccessor(ctx context.Context, id *NodeID) (info *ContactI
 	logger.Debug("FindSuccessor to: %s", id.String())
 
 	// if (id ∈ (n, successor] )
-	if id.Between(peer.Info.Id, successor.Id) {
+	if id.Between(peer.GetInfo().Id, successor.Id) {
 		// return successor;
 		info = successor
 		logger.Debug("returning: %v", info)
+	} else if cached := peer.cachedSuccessor(*id); cached != nil {
+		info = cached
+		logger.Debug("returning cached: %v", info)
 	} else {
 		// forward the query around the circle
 		// n0 = successor.closest_preceding_node(id);
@@ -145,12 +325,20 @@ func (peer *Peer) FindSuccessor(ctx context.Context, id *NodeID) (info *ContactI
 
 		// return n0.find_successor(id);
 		if err == nil {
-			successor, err = peer.network.FindSuccessor(n0, *id)
+			var found *ContactInfo
+			found, err = peer.network.FindSuccessor(n0, *id)
+			if ErrorKindOf(err) == Unavailable && !n0.Id.Equals(successor.Id) {
+				// n0 may be a stale finger of a node that has failed, going
+				// through our successor is slower but it has just answered
+				logger.Warn("forwarding lookup to %s failed, continuing through successor: %v", n0.Address, err)
+				found, err = peer.network.FindSuccessor(successor, *id)
+			}
 			if err != nil {
 				logger.Error("successor's FindSuccessor call failed: %v", err)
 				return
 			}
-			info = successor
+			info = found
+			peer.network.cache.AddLookup(*id, info)
 		}
 		logger.Debug("returning: %v", info)
 	}
@@ -158,18 +346,37 @@ func (peer *Peer) FindSuccessor(ctx context.Context, id *NodeID) (info *ContactI
 	return
 }
 
+// cachedSuccessor returns the cached node responsible for id, after checking
+// with a single Predecessor call that it still is.
+func (peer *Peer) cachedSuccessor(id NodeID) *ContactInfo {
+	cached := peer.network.cache.Lookup(id)
+	if cached == nil {
+		return nil
+	}
+
+	pred, err := peer.network.Predecessor(cached)
+	if err != nil || pred == nil || !id.Between(pred.Id, cached.Id) {
+		peer.network.cache.Remove(cached.Id)
+		return nil
+	}
+	return cached
+}
+
 func (peer *Peer) ClosestPrecedingNode(ctx context.Context, id *NodeID) (info *ContactInfo, err error) {
 	logger.Debug("ClosestPrecedingNode to: %s", id.String())
 
+	// The interval is open, a finger at id itself would be asked to look up
+	// its own id and send the lookup around the whole ring
+	fingers := peer.network.fingerTable.Fingers()
 	for i := fingerCount - 1; i >= 0; i-- {
-		finger := peer.network.fingerTable.fingers[i]
-		if finger != nil && finger.Id.Between(peer.Info.Id, *id) {
+		finger := fingers[i]
+		if finger != nil && finger.Id.Between(peer.GetInfo().Id, *id) && !finger.Id.Equals(*id) {
 			info = finger
 			return
 		}
 	}
 
-	info = peer.Info
+	info = peer.GetInfo()
 
 	return
 }
@@ -191,10 +398,64 @@ func (peer *Peer) Successor(ctx context.Context) (info *ContactInfo, err error)
 func (peer *Peer) Notify(ctx context.Context, sender *ContactInfo) (err error) {
 	logger.Debug("Notify: %s", sender.Address)
 
-	if peer.network.predecessor == nil || sender.Id.Between(peer.network.predecessor.Id, sender.Id) {
-		peer.network.predecessor = sender
+	pred := peer.network.getPredecessor()
+	switch {
+	case pred == nil || sender.Id.Between(pred.Id, peer.GetInfo().Id):
+		peer.network.setPredecessor(sender)
+		peer.network.watchers.Publish(peer.network.neighbours())
+		peer.Poke()
+	case sender.Id.Equals(pred.Id):
+		// Keep what the predecessor advertises up to date
+		peer.network.setPredecessor(sender)
+	case sender.Address == pred.Address && peer.network.moved(pred):
+		// The predecessor moved to a lower id, which is not between its
+		// old one and ours
+		logger.Info("predecessor %s moved from %s to %s", sender.Address, pred.Id.String(), sender.Id.String())
+		peer.network.members.Forget(pred)
+		peer.network.setPredecessor(sender)
+		peer.network.watchers.Publish(peer.network.neighbours())
 		peer.Poke()
 	}
 
 	return
-}
\ No newline at end of file
+}
+
+func (peer *Peer) SuccessorList(ctx context.Context) (list []*ContactInfo, err error) {
+	logger.Debug("SuccessorList")
+	for _, succ := range peer.network.successors.List() {
+		if succ != nil {
+			list = append(list, succ)
+		}
+	}
+
+	return
+}
+
+// Neighbours answers a stabilization round in one call, with the
+// predecessor as it is after taking notify into account.
+func (peer *Peer) Neighbours(ctx context.Context, notify *ContactInfo) (neighbours Neighbours, err error) {
+	logger.Debug("Neighbours")
+	if notify != nil {
+		if err = peer.Notify(ctx, notify); err != nil {
+			return
+		}
+	}
+
+	return peer.network.neighbours(), nil
+}
+
+func (peer *Peer) Store(ctx context.Context, item *Item) (err error) {
+	logger.Debug("Store: %s@%d", item.Key, item.Version)
+	peer.load.Record(item.Key)
+	peer.store.Put(item)
+
+	return
+}
+
+func (peer *Peer) Fetch(ctx context.Context, key string) (item *Item, err error) {
+	logger.Debug("Fetch: %s", key)
+	peer.load.Record(key)
+	item = peer.store.Get(key)
+
+	return
+}
@@ -0,0 +1,329 @@
+package chord
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Members of a topic's tree renew their place this often, and a child that
+// has not done so for topicChildTimeout is dropped.
+const topicRefreshInterval = 30 * time.Second
+const topicChildTimeout = 3 * topicRefreshInterval
+
+// TopicHandler handles the messages published to a topic the peer is
+// subscribed to.
+type TopicHandler func(ctx context.Context, topic string, payload []byte)
+
+// TopicMessage is a message published to a topic. It is routed to the
+// topic's rendezvous node and from there sent down the topic's tree.
+type TopicMessage struct {
+	Id        string
+	Topic     string
+	Payload   []byte
+	Publisher *ContactInfo
+	Hops      int
+	Final     bool
+}
+
+// topicState is a node's part in a topic's tree, as in Scribe: the node it
+// joined the tree through and the nodes that joined through it.
+type topicState struct {
+	parent   *ContactInfo
+	children map[string]*topicChild
+	handler  TopicHandler
+}
+
+type topicChild struct {
+	info      *ContactInfo
+	refreshed time.Time
+}
+
+// topicTable holds the trees a peer is part of, as a subscriber or as a
+// node on the path of other subscribers.
+type topicTable struct {
+	mutex  sync.Mutex
+	clock  Clock
+	topics map[string]*topicState
+}
+
+func newTopicTable() *topicTable {
+	return &topicTable{clock: realClock{}, topics: make(map[string]*topicState)}
+}
+
+func (table *topicTable) get(topic string) *topicState {
+	state, ok := table.topics[topic]
+	if !ok {
+		state = &topicState{children: make(map[string]*topicChild)}
+		table.topics[topic] = state
+	}
+	return state
+}
+
+// AddChild records that child joined the tree through us, and returns the
+// parent we have joined through, if any.
+func (table *topicTable) AddChild(topic string, child *ContactInfo) (parent *ContactInfo) {
+	table.mutex.Lock()
+	defer table.mutex.Unlock()
+	state := table.get(topic)
+	state.children[child.Id.String()] = &topicChild{info: child, refreshed: table.clock.Now()}
+	return state.parent
+}
+
+func (table *topicTable) RemoveChild(topic string, child *ContactInfo) {
+	table.mutex.Lock()
+	defer table.mutex.Unlock()
+	if state, ok := table.topics[topic]; ok {
+		delete(state.children, child.Id.String())
+	}
+}
+
+func (table *topicTable) SetParent(topic string, parent *ContactInfo) {
+	table.mutex.Lock()
+	defer table.mutex.Unlock()
+	table.get(topic).parent = parent
+}
+
+func (table *topicTable) SetHandler(topic string, handler TopicHandler) {
+	table.mutex.Lock()
+	defer table.mutex.Unlock()
+	table.get(topic).handler = handler
+}
+
+// Receivers returns the local handler and the children of topic, ordered by
+// id.
+func (table *topicTable) Receivers(topic string) (handler TopicHandler, children []*ContactInfo) {
+	table.mutex.Lock()
+	defer table.mutex.Unlock()
+	state, ok := table.topics[topic]
+	if !ok {
+		return
+	}
+	for _, child := range state.children {
+		children = append(children, child.info)
+	}
+	sortContacts(children)
+	return state.handler, children
+}
+
+// Expire drops the children that stopped refreshing, and the topics we no
+// longer have a reason to be in the tree of. It returns the remaining
+// topics and their parents, and the parents of the dropped topics.
+func (table *topicTable) Expire() (kept map[string]*ContactInfo, dropped map[string]*ContactInfo) {
+	table.mutex.Lock()
+	defer table.mutex.Unlock()
+
+	kept = make(map[string]*ContactInfo)
+	dropped = make(map[string]*ContactInfo)
+	now := table.clock.Now()
+	for topic, state := range table.topics {
+		for id, child := range state.children {
+			if now.Sub(child.refreshed) > topicChildTimeout {
+				delete(state.children, id)
+			}
+		}
+		if len(state.children) == 0 && state.handler == nil {
+			delete(table.topics, topic)
+			dropped[topic] = state.parent
+			continue
+		}
+		kept[topic] = state.parent
+	}
+	return
+}
+
+// Subscribe makes handler receive what is published to topic from now on.
+func (peer *Peer) Subscribe(ctx context.Context, topic string, handler TopicHandler) error {
+	peer.topics.SetHandler(topic, handler)
+	_, err := peer.joinTree(topic, nil)
+	return err
+}
+
+// Unsubscribe stops the messages of topic, the peer leaves the topic's
+// tree unless others joined it through the peer.
+func (peer *Peer) Unsubscribe(ctx context.Context, topic string) error {
+	peer.topics.SetHandler(topic, nil)
+	_, dropped := peer.topics.Expire()
+	peer.leaveTrees(dropped)
+	return nil
+}
+
+// Publish sends payload to every subscriber of topic.
+func (peer *Peer) Publish(ctx context.Context, topic string, payload []byte) error {
+	id := NewNodeIDFromHash(fmt.Sprintf("%s/%s/%d/%d", peer.GetInfo().Id.String(), topic, peer.network.clock.Now().UnixNano(), peer.network.random(1<<30)))
+	return peer.PublishMessage(ctx, &TopicMessage{
+		Id:        id.String(),
+		Topic:     topic,
+		Payload:   payload,
+		Publisher: peer.GetInfo(),
+	})
+}
+
+// PublishMessage routes msg to the rendezvous node of its topic, which
+// sends it down the topic's tree.
+func (peer *Peer) PublishMessage(ctx context.Context, msg *TopicMessage) error {
+	logger.Debug("PublishMessage: %s", msg.Topic)
+
+	key := NewNodeIDFromHash(msg.Topic)
+	if peer.owns(key, msg.Final) {
+		return peer.Multicast(ctx, msg)
+	}
+	if msg.Hops >= maxRouteHops {
+		return newError(FailedPrecondition, "message to topic %s exceeded %d hops", msg.Topic, maxRouteHops)
+	}
+
+	next := *msg
+	next.Hops++
+	var target *ContactInfo
+	target, next.Final = peer.nextHop(key)
+	return peer.network.PublishMessage(ctx, target, &next)
+}
+
+// Multicast delivers msg to the local subscriber, if any, and sends it on
+// to the peer's children in the topic's tree.
+func (peer *Peer) Multicast(ctx context.Context, msg *TopicMessage) error {
+	logger.Debug("Multicast: %s", msg.Topic)
+	if !peer.network.seen.Add(msg.Id) {
+		return nil
+	}
+
+	handler, children := peer.topics.Receivers(msg.Topic)
+	if handler != nil {
+		handler(ctx, msg.Topic, msg.Payload)
+	}
+	for _, child := range children {
+		child := child
+		peer.network.async(func() {
+			if err := peer.network.Multicast(context.Background(), child, msg); err != nil {
+				logger.Warn("failed to pass %s message on to %s: %v", msg.Topic, child.Address, err)
+			}
+		})
+	}
+	return nil
+}
+
+// JoinTopic adds child to the topic's tree below us, joining the tree
+// ourselves if we were not part of it yet.
+func (peer *Peer) JoinTopic(ctx context.Context, topic string, child *ContactInfo, final bool) error {
+	logger.Debug("JoinTopic: %s from %s", topic, child.Address)
+	if parent := peer.topics.AddChild(topic, child); parent != nil {
+		return nil
+	}
+	_, err := peer.joinTree(topic, &final)
+	return err
+}
+
+func (peer *Peer) LeaveTopic(ctx context.Context, topic string, child *ContactInfo) error {
+	logger.Debug("LeaveTopic: %s from %s", topic, child.Address)
+	peer.topics.RemoveChild(topic, child)
+	return nil
+}
+
+// joinTree joins the topic's tree through the next node on the way to its
+// rendezvous node, unless we are the rendezvous node. final is the mark of
+// the join that made us part of the tree, if any.
+func (peer *Peer) joinTree(topic string, final *bool) (parent *ContactInfo, err error) {
+	key := NewNodeIDFromHash(topic)
+	if peer.owns(key, final != nil && *final) {
+		peer.topics.SetParent(topic, nil)
+		return nil, nil
+	}
+
+	parent, hopFinal := peer.nextHop(key)
+	if err = peer.network.JoinTopic(parent, topic, hopFinal); err != nil {
+		peer.topics.SetParent(topic, nil)
+		return nil, err
+	}
+	peer.topics.SetParent(topic, parent)
+	return parent, nil
+}
+
+// RefreshTopics renews our place in the trees we are part of, and moves to
+// a new parent where the routing state has changed since we joined. Trees
+// repair themselves this way as nodes join and fail.
+func (peer *Peer) RefreshTopics() (err error) {
+	kept, dropped := peer.topics.Expire()
+	peer.leaveTrees(dropped)
+
+	for _, topic := range sortedTopics(kept) {
+		old := kept[topic]
+		var parent *ContactInfo
+		if parent, err = peer.joinTree(topic, nil); err != nil {
+			logger.Warn("failed to rejoin the tree of topic %s: %v", topic, err)
+			continue
+		}
+		if old != nil && (parent == nil || !parent.Id.Equals(old.Id)) {
+			logger.Info("topic %s moved from parent %s", topic, old.Address)
+			peer.network.LeaveTopic(old, topic)
+		}
+	}
+	return
+}
+
+// leaveTrees tells the parents of the topics we dropped. A parent that does
+// not hear it drops us once we stop refreshing.
+func (peer *Peer) leaveTrees(dropped map[string]*ContactInfo) {
+	for _, topic := range sortedTopics(dropped) {
+		parent := dropped[topic]
+		if parent == nil {
+			continue
+		}
+		if err := peer.network.LeaveTopic(parent, topic); err != nil {
+			logger.Debug("failed to leave the tree of topic %s: %v", topic, err)
+		}
+	}
+}
+
+func sortedTopics(parents map[string]*ContactInfo) (topics []string) {
+	for topic := range parents {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+	return
+}
+
+func (network *chordNetwork) JoinTopic(info *ContactInfo, topic string, final bool) (err error) {
+	if !network.supports(info, CapPubSub) {
+		return unsupported(info, "JoinTopic")
+	}
+	err = network.Call(info, func(client ChordClient) error {
+		err = client.JoinTopic(context.Background(), topic, network.self(), final)
+		return err
+	})
+	return
+}
+
+func (network *chordNetwork) LeaveTopic(info *ContactInfo, topic string) (err error) {
+	if !network.supports(info, CapPubSub) {
+		return unsupported(info, "LeaveTopic")
+	}
+	err = network.Call(info, func(client ChordClient) error {
+		err = client.LeaveTopic(context.Background(), topic, network.self())
+		return err
+	})
+	return
+}
+
+func (network *chordNetwork) PublishMessage(ctx context.Context, info *ContactInfo, msg *TopicMessage) (err error) {
+	if !network.supports(info, CapPubSub) {
+		return unsupported(info, "Publish")
+	}
+	err = network.Call(info, func(client ChordClient) error {
+		err = client.Publish(ctx, msg)
+		return err
+	})
+	return
+}
+
+func (network *chordNetwork) Multicast(ctx context.Context, info *ContactInfo, msg *TopicMessage) (err error) {
+	if !network.supports(info, CapPubSub) {
+		return unsupported(info, "Multicast")
+	}
+	err = network.Call(info, func(client ChordClient) error {
+		err = client.Multicast(ctx, msg)
+		return err
+	})
+	return
+}
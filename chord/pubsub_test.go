@@ -0,0 +1,135 @@
+package chord_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lukaspj/go-chord/chord"
+)
+
+// inbox records which peers received which payloads.
+type inbox struct {
+	mutex    sync.Mutex
+	received map[string]map[string]bool
+}
+
+func subscribeAll(t *testing.T, peers []*chord.Peer, topic string) *inbox {
+	box := &inbox{received: make(map[string]map[string]bool)}
+	for _, peer := range peers {
+		address := peer.GetInfo().Address
+		err := peer.Subscribe(context.Background(), topic, func(ctx context.Context, topic string, payload []byte) {
+			box.mutex.Lock()
+			defer box.mutex.Unlock()
+			if box.received[address] == nil {
+				box.received[address] = make(map[string]bool)
+			}
+			box.received[address][string(payload)] = true
+		})
+		if err != nil {
+			t.Fatalf("%s failed to subscribe: %v", address, err)
+		}
+	}
+	return box
+}
+
+// missing returns the peers that have not received payload.
+func (box *inbox) missing(peers []*chord.Peer, payload string) (addresses []string) {
+	box.mutex.Lock()
+	defer box.mutex.Unlock()
+	for _, peer := range peers {
+		if !box.received[peer.GetInfo().Address][payload] {
+			addresses = append(addresses, peer.GetInfo().Address)
+		}
+	}
+	return
+}
+
+// waitFor waits until every one of peers has received payload.
+func (box *inbox) waitFor(peers []*chord.Peer, payload string, timeout time.Duration) []string {
+	deadline := time.Now().Add(timeout)
+	for {
+		missing := box.missing(peers, payload)
+		if len(missing) == 0 || time.Now().After(deadline) {
+			return missing
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestPublishReachesSubscribersOnly(t *testing.T) {
+	ring := startRing(t, 6, nil)
+	defer ring.Stop()
+
+	peers := ring.Running()
+	subscribers := []*chord.Peer{peers[0], peers[2], peers[3]}
+	box := subscribeAll(t, subscribers, "news")
+
+	// Published through a peer that is not subscribed itself
+	if err := peers[5].Publish(context.Background(), "news", []byte("first")); err != nil {
+		t.Fatal(err)
+	}
+	if missing := box.waitFor(subscribers, "first", 5*time.Second); len(missing) > 0 {
+		t.Errorf("subscribers %v did not receive the message", missing)
+	}
+	for _, peer := range []*chord.Peer{peers[1], peers[4], peers[5]} {
+		if len(box.missing([]*chord.Peer{peer}, "first")) == 0 {
+			t.Errorf("%s received the message without subscribing", peer.GetInfo().Address)
+		}
+	}
+}
+
+func TestTopicTreeRepairsAfterInteriorNodeFails(t *testing.T) {
+	// Declare the failed node dead quickly, the test is about what follows
+	ring := startRing(t, 6, func(peer *chord.Peer) {
+		peer.FailureDetector.SuspectTimeout = time.Second
+	})
+	defer ring.Stop()
+
+	topic := "repairs"
+	box := subscribeAll(t, ring.Running(), topic)
+
+	// Every path to the rendezvous node ends at its predecessor, so the
+	// other subscribers hang below it in the tree
+	peers := ring.Running()
+	rendezvous := ring.Responsible(chord.NewNodeIDFromHash(topic))
+	var interior *chord.Peer
+	for i, peer := range peers {
+		if peer == rendezvous {
+			interior = peers[(i+len(peers)-1)%len(peers)]
+		}
+	}
+
+	if err := peers[0].Publish(context.Background(), topic, []byte("before")); err != nil {
+		t.Fatal(err)
+	}
+	if missing := box.waitFor(peers, "before", 5*time.Second); len(missing) > 0 {
+		t.Fatalf("subscribers %v did not receive the message before the failure", missing)
+	}
+
+	ring.Fail(interior)
+	if err := ring.WaitForConvergence(convergenceTimeout); err != nil {
+		t.Fatal(err)
+	}
+
+	// The routing changes nudge the subscribers to rejoin the tree well
+	// before their next refresh, 30 seconds after the last one
+	const repairTimeout = 10 * time.Second
+	survivors := ring.Running()
+	deadline := time.Now().Add(repairTimeout)
+	for attempt := 0; ; attempt++ {
+		payload := fmt.Sprintf("after %d", attempt)
+		if err := survivors[0].Publish(context.Background(), topic, []byte(payload)); err != nil {
+			t.Fatalf("publish after the ring converged failed: %v", err)
+		}
+		missing := box.waitFor(survivors, payload, time.Second)
+		if len(missing) == 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("subscribers %v were still cut off from the tree after %v", missing, repairTimeout)
+		}
+	}
+}
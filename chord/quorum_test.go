@@ -0,0 +1,87 @@
+package chord
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"testing"
+)
+
+func TestQuorumValidate(t *testing.T) {
+	tests := []struct {
+		quorum Quorum
+		valid  bool
+	}{
+		{DefaultQuorum, true},
+		{Quorum{N: 1, R: 1, W: 1}, true},
+		{Quorum{N: successorListSize + 1, R: 1, W: successorListSize + 1}, true},
+		{Quorum{N: 0, R: 1, W: 1}, false},
+		{Quorum{N: successorListSize + 2, R: 1, W: 1}, false},
+		{Quorum{N: 3, R: 0, W: 2}, false},
+		{Quorum{N: 3, R: 4, W: 2}, false},
+		{Quorum{N: 3, R: 2, W: 0}, false},
+		{Quorum{N: 3, R: 2, W: 4}, false},
+	}
+	for _, test := range tests {
+		if err := test.quorum.Validate(); (err == nil) != test.valid {
+			t.Errorf("Validate(%+v) returned %v, expected valid=%v", test.quorum, err, test.valid)
+		}
+	}
+}
+
+func TestNewestItem(t *testing.T) {
+	answers := []fetchResult{
+		{item: &Item{Key: "k", Version: 2}},
+		{item: nil},
+		{item: &Item{Key: "k", Version: 5}},
+		{item: &Item{Key: "k", Version: 3}},
+	}
+	if newest := newestItem(answers); newest == nil || newest.Version != 5 {
+		t.Errorf("newest item is %+v, expected version 5", newest)
+	}
+	if newest := newestItem([]fetchResult{{}, {}}); newest != nil {
+		t.Errorf("newest item of answers without the key is %+v, expected nil", newest)
+	}
+}
+
+// listeningPeer starts a peer in a ring of its own on a free loopback port.
+func listeningPeer(t *testing.T) *Peer {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+
+	address := fmt.Sprintf("127.0.0.1:%d", port)
+	peer := NewPeer(&ContactInfo{Address: address, Id: NewNodeIDFromHash(address)}, port)
+	peer.Listen()
+	return &peer
+}
+
+func TestPutGetOnSingleNode(t *testing.T) {
+	peer := listeningPeer(t)
+	ctx := context.Background()
+
+	if item, err := peer.Get(ctx, "missing"); err != nil || item != nil {
+		t.Fatalf("get of a missing key returned %+v, %v, expected nothing", item, err)
+	}
+
+	for _, value := range []string{"first", "second"} {
+		if err := peer.Put(ctx, "key", []byte(value)); err != nil {
+			t.Fatalf("put of %q failed: %v", value, err)
+		}
+		item, err := peer.Get(ctx, "key")
+		switch {
+		case err != nil:
+			t.Fatalf("get failed: %v", err)
+		case item == nil || !bytes.Equal(item.Value, []byte(value)):
+			t.Fatalf("get returned %+v, expected %q", item, value)
+		}
+	}
+
+	if err := peer.PutWithQuorum(ctx, "key", []byte("x"), Quorum{N: 3, R: 4, W: 1}); err == nil {
+		t.Errorf("put with an invalid quorum succeeded")
+	}
+}
@@ -0,0 +1,327 @@
+package chord
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// Quorum holds the Dynamo-style replication settings: every key is stored on
+// the node responsible for it and its first N-1 successors, a write needs W
+// acknowledgements and a read needs R answers.
+type Quorum struct {
+	N int `json:"n"`
+	R int `json:"r"`
+	W int `json:"w"`
+}
+
+var DefaultQuorum = Quorum{N: 3, R: 2, W: 2}
+
+func (quorum Quorum) Validate() error {
+	if quorum.N < 1 || quorum.N > successorListSize+1 {
+		return invalidArgument("quorum", "quorum N must be between 1 and %d, got %d", successorListSize+1, quorum.N)
+	}
+	if quorum.R < 1 || quorum.R > quorum.N {
+		return invalidArgument("quorum", "quorum R must be between 1 and N (%d), got %d", quorum.N, quorum.R)
+	}
+	if quorum.W < 1 || quorum.W > quorum.N {
+		return invalidArgument("quorum", "quorum W must be between 1 and N (%d), got %d", quorum.N, quorum.W)
+	}
+	return nil
+}
+
+// Replicas returns the preference list for id: the node responsible for it
+// followed by its successors, at most n nodes and never two on the same
+// address, so virtual nodes of one host or a ring smaller than n do not
+// count twice. The successors past the first n are returned as
+// fallbacks that can hold hints for replicas that are down. A responsible
+// node that is down keeps its place, its successors are learned from the
+// first node after it that answers.
+func (network *chordNetwork) Replicas(id NodeID, n int) (replicas, fallbacks []*ContactInfo, err error) {
+	var responsible *ContactInfo
+	responsible, err = network.FindSuccessor(network.self(), id)
+	if err != nil {
+		return
+	}
+	if responsible == nil {
+		err = newError(Internal, "no node is responsible for %s", id.String())
+		return
+	}
+
+	var successors []*ContactInfo
+	successors, err = network.successorsOf(responsible)
+	if unreachable(err, responsible) {
+		// The responsible node stays first in the list, so that its writes
+		// are hinted and handed back to it when it returns
+		logger.Warn("%s did not answer, building the preference list for %s without it: %v", responsible.Address, id.String(), err)
+		successors, err = network.successorsAfter(responsible)
+	}
+	if err != nil {
+		return
+	}
+
+	replicas, fallbacks = network.preferenceList(append([]*ContactInfo{responsible}, successors...), n)
+	return
+}
+
+// preferenceList picks the first n nodes that store items, on distinct
+// addresses, from nodes in ring order. Nodes that do not store items are
+// skipped, the next ones along the ring take their place for both writes
+// and reads. The rest are the fallbacks.
+func (network *chordNetwork) preferenceList(nodes []*ContactInfo, n int) (replicas, fallbacks []*ContactInfo) {
+	var candidates []*ContactInfo
+	for _, node := range nodes {
+		duplicate := false
+		for _, c := range candidates {
+			duplicate = duplicate || c.Address == node.Address
+		}
+		if !duplicate && network.supports(node, CapStorage) {
+			candidates = append(candidates, node)
+		}
+	}
+
+	if len(candidates) <= n {
+		return candidates, nil
+	}
+	return candidates[:n], candidates[n:]
+}
+
+// successorsOf returns the successor list of info, walking it for nodes that
+// do not serve SuccessorList.
+func (network *chordNetwork) successorsOf(info *ContactInfo) (list []*ContactInfo, err error) {
+	list, err = network.SuccessorList(info)
+	if ErrorKindOf(err) == Unsupported {
+		list, err = network.walkSuccessors(info, successorListSize)
+	}
+	return
+}
+
+// successorsAfter returns the first node following info that answers,
+// followed by its successors, without asking info. The candidates come from
+// our own successor list and membership view.
+func (network *chordNetwork) successorsAfter(info *ContactInfo) (list []*ContactInfo, err error) {
+	candidates := append([]*ContactInfo{network.self()}, network.successors.List()...)
+	candidates = append(candidates, network.members.alive(network.detector)...)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return info.Id.Distance(candidates[i].Id).Cmp(info.Id.Distance(candidates[j].Id)) < 0
+	})
+
+	tried := map[string]bool{info.Id.String(): true}
+	for _, c := range candidates {
+		if len(tried) > successorListSize {
+			break
+		}
+		if c == nil || c.Id.IsZero() || tried[c.Id.String()] {
+			continue
+		}
+		tried[c.Id.String()] = true
+
+		if c.Id.Equals(network.self().Id) {
+			return append([]*ContactInfo{c}, network.successors.List()...), nil
+		}
+		var rest []*ContactInfo
+		if rest, err = network.successorsOf(c); err == nil {
+			return append([]*ContactInfo{c}, rest...), nil
+		}
+		logger.Warn("%s did not answer either: %v", c.Address, err)
+	}
+	return nil, newError(Unavailable, "no node following %s answered", info.Address)
+}
+
+// walkSuccessors follows Successor pointers from info for at most n nodes,
+// for nodes that do not serve SuccessorList.
+func (network *chordNetwork) walkSuccessors(info *ContactInfo, n int) (list []*ContactInfo, err error) {
+	current := info
+	for i := 0; i < n; i++ {
+		if current, err = network.Successor(current); err != nil {
+			return
+		}
+		list = append(list, current)
+	}
+	return
+}
+
+// required caps a quorum size to the number of replicas that exist, so a ring
+// with fewer than N nodes can still serve requests.
+func required(quorumSize int, replicas []*ContactInfo) int {
+	if quorumSize > len(replicas) {
+		return len(replicas)
+	}
+	return quorumSize
+}
+
+func (peer *Peer) Put(ctx context.Context, key string, value []byte) error {
+	return peer.PutWithQuorum(ctx, key, value, peer.Quorum)
+}
+
+func (peer *Peer) PutWithQuorum(ctx context.Context, key string, value []byte, quorum Quorum) (err error) {
+	if err = quorum.Validate(); err != nil {
+		return
+	}
+
+	var replicas, fallbacks []*ContactInfo
+	if replicas, fallbacks, err = peer.network.Replicas(NewNodeIDFromHash(key), quorum.N); err != nil {
+		return
+	}
+
+	item := &Item{
+		Key:     key,
+		Value:   value,
+		Version: uint64(peer.network.clock.Now().UnixNano()),
+	}
+
+	handoff := &hintedHandoff{peer: peer, fallbacks: fallbacks}
+	acks := make(chan error, len(replicas))
+	for _, replica := range replicas {
+		replica := replica
+		peer.network.async(func() {
+			err := peer.network.Store(replica, item)
+			if err != nil {
+				logger.Warn("replica %s failed to store %s, leaving a hint: %v", replica.Address, key, err)
+				err = handoff.Hint(replica, item)
+			}
+			acks <- err
+		})
+	}
+
+	w := required(quorum.W, replicas)
+	succeeded := 0
+	for range replicas {
+		select {
+		case err = <-acks:
+			if err != nil {
+				logger.Warn("failed to store %s: %v", key, err)
+				continue
+			}
+			succeeded++
+			if succeeded >= w {
+				return nil
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return newError(Unavailable, "write of %s was acknowledged by %d replicas, %d required", key, succeeded, w)
+}
+
+// hintedHandoff hands out the fallback nodes of a single write, so that every
+// unreachable replica leaves its hint on a different live successor.
+type hintedHandoff struct {
+	mutex     sync.Mutex
+	peer      *Peer
+	fallbacks []*ContactInfo
+}
+
+// Hint stores item on the next fallback that accepts it. Each fallback is a
+// live node that holds no other copy of the write, so the hint counts toward
+// the write quorum in place of the replica. Once every fallback has been
+// used up the hint is kept on this peer, so the write is not dropped, but it
+// does not count.
+func (handoff *hintedHandoff) Hint(target *ContactInfo, item *Item) error {
+	for {
+		handoff.mutex.Lock()
+		if len(handoff.fallbacks) == 0 {
+			handoff.mutex.Unlock()
+			handoff.peer.StoreHint(context.Background(), target, item)
+			return newError(Unavailable, "no fallback node took the hint for %s", target.Address)
+		}
+		fallback := handoff.fallbacks[0]
+		handoff.fallbacks = handoff.fallbacks[1:]
+		handoff.mutex.Unlock()
+
+		if err := handoff.peer.network.StoreHint(fallback, target, item); err == nil {
+			return nil
+		}
+		logger.Warn("fallback %s failed to store hint for %s", fallback.Address, target.Address)
+	}
+}
+
+type fetchResult struct {
+	replica *ContactInfo
+	item    *Item
+	err     error
+}
+
+func (peer *Peer) Get(ctx context.Context, key string) (*Item, error) {
+	return peer.GetWithQuorum(ctx, key, peer.Quorum)
+}
+
+// GetWithQuorum returns the newest item among the first R answers, or nil if
+// none of them has the key. The remaining answers are awaited in the
+// background and every replica holding a stale copy is repaired.
+func (peer *Peer) GetWithQuorum(ctx context.Context, key string, quorum Quorum) (item *Item, err error) {
+	if err = quorum.Validate(); err != nil {
+		return
+	}
+
+	var replicas []*ContactInfo
+	if replicas, _, err = peer.network.Replicas(NewNodeIDFromHash(key), quorum.N); err != nil {
+		return
+	}
+
+	results := make(chan fetchResult, len(replicas))
+	for _, replica := range replicas {
+		replica := replica
+		peer.network.async(func() {
+			res, err := peer.network.Fetch(replica, key)
+			results <- fetchResult{replica: replica, item: res, err: err}
+		})
+	}
+
+	r := required(quorum.R, replicas)
+	pending := len(replicas)
+	var answers []fetchResult
+	for len(answers) < r && pending > 0 {
+		select {
+		case res := <-results:
+			pending--
+			if res.err != nil {
+				logger.Warn("replica failed to fetch %s: %v", key, res.err)
+				continue
+			}
+			answers = append(answers, res)
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	if len(answers) < r {
+		return nil, newError(Unavailable, "read of %s was answered by %d replicas, %d required", key, len(answers), r)
+	}
+
+	item = newestItem(answers)
+	peer.network.async(func() { peer.readRepair(answers, results, pending) })
+	return
+}
+
+func newestItem(answers []fetchResult) (newest *Item) {
+	for _, answer := range answers {
+		if answer.item != nil && (newest == nil || answer.item.Version > newest.Version) {
+			newest = answer.item
+		}
+	}
+	return
+}
+
+func (peer *Peer) readRepair(answers []fetchResult, results chan fetchResult, pending int) {
+	for ; pending > 0; pending-- {
+		if res := <-results; res.err == nil {
+			answers = append(answers, res)
+		}
+	}
+
+	newest := newestItem(answers)
+	if newest == nil {
+		return
+	}
+
+	for _, answer := range answers {
+		if answer.item == nil || answer.item.Version < newest.Version {
+			logger.Info("repairing %s on %s", newest.Key, answer.replica.Address)
+			if err := peer.network.Store(answer.replica, newest); err != nil {
+				logger.Warn("read repair of %s on %s failed: %v", newest.Key, answer.replica.Address, err)
+			}
+		}
+	}
+}
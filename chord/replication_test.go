@@ -0,0 +1,121 @@
+package chord_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lukaspj/go-chord/chord"
+	"github.com/lukaspj/go-chord/chordtest"
+)
+
+const convergenceTimeout = 30 * time.Second
+
+func startRing(t *testing.T, n int, configure func(peer *chord.Peer)) *chordtest.Ring {
+	t.Helper()
+	ring, err := chordtest.Start(n, configure)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = ring.WaitForConvergence(convergenceTimeout); err != nil {
+		ring.Stop()
+		t.Fatal(err)
+	}
+	return ring
+}
+
+func TestQuorumSurvivesPrimaryCrash(t *testing.T) {
+	ring := startRing(t, 5, nil)
+	defer ring.Stop()
+
+	ctx := context.Background()
+	key := "primary-crash"
+	primary := ring.Responsible(chord.NewNodeIDFromHash(key))
+	if err := primary.Put(ctx, key, []byte("before")); err != nil {
+		t.Fatalf("put before the crash failed: %v", err)
+	}
+
+	ring.Fail(primary)
+
+	// Nobody has noticed the crash yet, the lookups still end at the primary
+	for _, peer := range ring.Running() {
+		value := []byte("after " + peer.GetInfo().Address)
+		if err := peer.Put(ctx, key, value); err != nil {
+			t.Fatalf("put through %s failed: %v", peer.GetInfo().Address, err)
+		}
+		item, err := peer.Get(ctx, key)
+		switch {
+		case err != nil:
+			t.Fatalf("get through %s failed: %v", peer.GetInfo().Address, err)
+		case item == nil:
+			t.Fatalf("get through %s found nothing", peer.GetInfo().Address)
+		case !bytes.Equal(item.Value, value):
+			t.Fatalf("get through %s returned %q, expected %q", peer.GetInfo().Address, item.Value, value)
+		}
+	}
+}
+
+func TestHintedHandoffAfterOutage(t *testing.T) {
+	ring := startRing(t, 5, nil)
+	defer ring.Stop()
+
+	ctx := context.Background()
+	key := "outage"
+	primary := ring.Responsible(chord.NewNodeIDFromHash(key))
+	ring.Fail(primary)
+
+	value := []byte("written during the outage")
+	if err := ring.Running()[0].Put(ctx, key, value); err != nil {
+		t.Fatalf("put during the outage failed: %v", err)
+	}
+	if item, _ := primary.Fetch(ctx, key); item != nil {
+		t.Fatalf("the primary has %q while it is down", item.Value)
+	}
+
+	if err := ring.Restart(primary); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ring.WaitForConvergence(convergenceTimeout); err != nil {
+		t.Fatal(err)
+	}
+
+	// The check-predecessor task replays hints every 20 seconds, do it now
+	for _, peer := range ring.Running() {
+		peer.ReplayHints()
+	}
+	if item, _ := primary.Fetch(ctx, key); item == nil || !bytes.Equal(item.Value, value) {
+		t.Fatalf("the primary did not get the hinted write back, it has %v", item)
+	}
+}
+
+func TestWriteQuorumNeedsLiveNodes(t *testing.T) {
+	quorum := chord.Quorum{N: 3, R: 1, W: 3}
+	ctx := context.Background()
+	key := "sloppy"
+
+	// With a fourth node to take the hint, the write has its three copies
+	ring := startRing(t, 4, nil)
+	defer ring.Stop()
+	// The primary's predecessor knows it owns the key without asking the
+	// failed node
+	primary := ring.Responsible(chord.NewNodeIDFromHash(key))
+	coordinator := ring.Responsible(primary.GetPredecessor().Id)
+	ring.Fail(ring.Responsible(primary.GetSuccessor().Id))
+	if err := coordinator.PutWithQuorum(ctx, key, []byte("hinted"), quorum); err != nil {
+		t.Errorf("put with a live fallback failed: %v", err)
+	}
+
+	// Without one, only two nodes hold the write, a hint the coordinator
+	// keeps itself does not make up the third
+	small := startRing(t, 3, nil)
+	defer small.Stop()
+	primary = small.Responsible(chord.NewNodeIDFromHash(key))
+	coordinator = small.Responsible(primary.GetPredecessor().Id)
+	small.Fail(small.Responsible(primary.GetSuccessor().Id))
+	err := coordinator.PutWithQuorum(ctx, key, []byte("unhinted"), quorum)
+	if chord.ErrorKindOf(err) != chord.Unavailable {
+		t.Errorf("put without a live fallback returned %v, expected it to be unavailable", err)
+	}
+}
@@ -0,0 +1,136 @@
+package chord
+
+import (
+	"context"
+)
+
+// A message that has been forwarded this many times is dropped, it is
+// going around in circles while the ring settles.
+const maxRouteHops = 64
+
+// Message is an application message routed to the node responsible for Key.
+type Message struct {
+	Key     NodeID
+	Payload []byte
+	// Origin is the node that sent the message.
+	Origin *ContactInfo
+	// Hops counts how often the message has been forwarded.
+	Hops int
+	// Final is set by the predecessor of the node it is forwarded to, when
+	// that node should be the one responsible for Key.
+	Final bool
+}
+
+// Reply is what the handler of a routed message answered with.
+type Reply struct {
+	Payload []byte
+	// Node is the node that handled the message.
+	Node *ContactInfo
+	Hops int
+}
+
+// MessageHandler handles the messages routed to keys the peer is
+// responsible for, what it returns is sent back to the sender.
+type MessageHandler func(ctx context.Context, msg *Message) ([]byte, error)
+
+// Route sends payload to whichever node is responsible for key and returns
+// that node's reply.
+func (peer *Peer) Route(ctx context.Context, key NodeID, payload []byte) ([]byte, error) {
+	reply, err := peer.RouteMessage(ctx, &Message{Key: key, Payload: payload, Origin: peer.GetInfo()})
+	if err != nil {
+		return nil, err
+	}
+	return reply.Payload, nil
+}
+
+// RouteMessage handles msg if the peer is responsible for its key, and
+// otherwise forwards it over the finger table. The reply comes back the
+// way the message went.
+func (peer *Peer) RouteMessage(ctx context.Context, msg *Message) (reply *Reply, err error) {
+	logger.Debug("RouteMessage to: %s", msg.Key.String())
+
+	if peer.owns(msg.Key, msg.Final) {
+		handler := peer.Handler
+		if handler == nil {
+			return nil, &Error{Kind: FailedPrecondition, Node: peer.GetInfo(), Message: "no message handler registered"}
+		}
+		reply = &Reply{Node: peer.GetInfo(), Hops: msg.Hops}
+		reply.Payload, err = handler(ctx, msg)
+		return
+	}
+	if msg.Hops >= maxRouteHops {
+		return nil, newError(FailedPrecondition, "message to %s exceeded %d hops", msg.Key.String(), maxRouteHops)
+	}
+
+	next := *msg
+	next.Hops++
+	var target *ContactInfo
+	target, next.Final = peer.nextHop(msg.Key)
+
+	reply, err = peer.network.RouteMessage(ctx, target, &next)
+	if !unreachable(err, target) || msg.Key.Between(peer.GetInfo().Id, target.Id) {
+		// Unless the node that is down was the one responsible, and there
+		// is no one to hand the message to until the ring has repaired
+		return
+	}
+
+	// A stale finger or a failed successor, continue through the first
+	// successor that answers
+	logger.Warn("forwarding message to %s failed, continuing through successors: %v", target.Address, err)
+	for _, succ := range peer.network.successors.List() {
+		if succ == nil || succ.Id.Equals(peer.GetInfo().Id) || succ.Id.Equals(target.Id) {
+			continue
+		}
+		next.Final = msg.Key.Between(peer.GetInfo().Id, succ.Id)
+		if reply, err = peer.network.RouteMessage(ctx, succ, &next); !unreachable(err, succ) {
+			return
+		}
+	}
+	return
+}
+
+// nextHop returns the node a message for key is forwarded to, and whether
+// that node should be the one responsible for key.
+func (peer *Peer) nextHop(key NodeID) (target *ContactInfo, final bool) {
+	successor := peer.GetSuccessor()
+	if key.Between(peer.GetInfo().Id, successor.Id) {
+		return successor, true
+	}
+	if target, _ = peer.ClosestPrecedingNode(context.Background(), &key); target.Id.Equals(peer.GetInfo().Id) {
+		target = successor
+	}
+	return target, false
+}
+
+// owns tells whether the peer should handle a message for key. A message
+// its predecessor marked final is handled unless the peer knows of a closer
+// predecessor, a peer without a predecessor could not tell otherwise.
+func (peer *Peer) owns(key NodeID, final bool) bool {
+	pred := peer.GetPredecessor()
+	switch {
+	case pred != nil:
+		return key.Between(pred.Id, peer.GetInfo().Id)
+	case final:
+		return true
+	default:
+		return peer.GetSuccessor().Id.Equals(peer.GetInfo().Id)
+	}
+}
+
+// unreachable tells whether err means that target itself could not be
+// reached, rather than that something further along failed.
+func unreachable(err error, target *ContactInfo) bool {
+	e, ok := err.(*Error)
+	return ok && e.Kind == Unavailable && e.Node != nil && e.Node.Id.Equals(target.Id)
+}
+
+func (network *chordNetwork) RouteMessage(ctx context.Context, info *ContactInfo, msg *Message) (res *Reply, err error) {
+	if !network.supports(info, CapRoute) {
+		return nil, unsupported(info, "Route")
+	}
+	err = network.Call(info, func(client ChordClient) error {
+		res, err = client.Route(ctx, msg)
+		return err
+	})
+	return
+}
@@ -0,0 +1,58 @@
+package chord
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRouteCountsHops(t *testing.T) {
+	const n = 8
+	peers := wiredRing(t, n)
+	defer stopAll(peers)
+	for _, peer := range peers {
+		peer.Handler = func(ctx context.Context, msg *Message) ([]byte, error) {
+			return msg.Payload, nil
+		}
+	}
+	origin := peers[0]
+
+	route := func(key NodeID) *Reply {
+		reply, err := origin.RouteMessage(context.Background(), &Message{Key: key, Payload: []byte("hi"), Origin: origin.GetInfo()})
+		if err != nil {
+			t.Fatalf("routing to %s: %v", key.String(), err)
+		}
+		if string(reply.Payload) != "hi" {
+			t.Errorf("reply to %s is %q, expected the payload back", key.String(), reply.Payload)
+		}
+		return reply
+	}
+
+	if reply := route(origin.GetInfo().Id); reply.Hops != 0 || !sameNode(reply.Node, origin.GetInfo()) {
+		t.Errorf("own key was handled by %s after %d hops, expected the origin after none", reply.Node.Address, reply.Hops)
+	}
+	if reply := route(peers[1].GetInfo().Id); reply.Hops != 1 || !sameNode(reply.Node, peers[1].GetInfo()) {
+		t.Errorf("successor's key was handled by %s after %d hops, expected the successor after one", reply.Node.Address, reply.Hops)
+	}
+
+	// Every hop over the fingers at least halves the distance left, a few
+	// hops more than log n leaves room for uneven ids
+	for _, peer := range peers {
+		reply := route(peer.GetInfo().Id)
+		if !sameNode(reply.Node, peer.GetInfo()) {
+			t.Errorf("key of %s was handled by %s", peer.GetInfo().Address, reply.Node.Address)
+		}
+		if reply.Hops > 6 {
+			t.Errorf("key of %s took %d hops in a ring of %d", peer.GetInfo().Address, reply.Hops, n)
+		}
+	}
+}
+
+func TestRouteDropsMessagesGoingInCircles(t *testing.T) {
+	peers := wiredRing(t, 3)
+	defer stopAll(peers)
+
+	msg := &Message{Key: peers[1].GetInfo().Id, Origin: peers[0].GetInfo(), Hops: maxRouteHops}
+	if _, err := peers[0].RouteMessage(context.Background(), msg); ErrorKindOf(err) != FailedPrecondition {
+		t.Errorf("message past %d hops failed with %v, expected it to be dropped", maxRouteHops, err)
+	}
+}
@@ -0,0 +1,40 @@
+package chord
+
+import (
+	"sync"
+	"time"
+)
+
+// Weight of a new sample in the smoothed round-trip time.
+const rttSmoothing = 0.2
+
+// rttTable keeps a smoothed round-trip time per address, measured on the
+// ordinary RPC traffic.
+type rttTable struct {
+	mutex sync.Mutex
+	rtts  map[string]time.Duration
+}
+
+func newRTTTable() *rttTable {
+	return &rttTable{
+		rtts: make(map[string]time.Duration),
+	}
+}
+
+func (table *rttTable) Observe(address string, sample time.Duration) {
+	table.mutex.Lock()
+	defer table.mutex.Unlock()
+
+	if current, ok := table.rtts[address]; ok {
+		sample = time.Duration((1-rttSmoothing)*float64(current) + rttSmoothing*float64(sample))
+	}
+	table.rtts[address] = sample
+}
+
+func (table *rttTable) Get(address string) (rtt time.Duration, ok bool) {
+	table.mutex.Lock()
+	defer table.mutex.Unlock()
+
+	rtt, ok = table.rtts[address]
+	return
+}
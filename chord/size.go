@@ -0,0 +1,91 @@
+package chord
+
+import (
+	"math"
+)
+
+// SizeEstimate is a node's estimate of the number of nodes in the ring.
+type SizeEstimate struct {
+	Size float64 `json:"size"`
+	// Low and High bound the likely sizes. Low is the larger of the number
+	// of nodes the node knows to be alive, which the ring is at least as
+	// large as, and the lower bound of the density estimate.
+	Low  float64 `json:"low"`
+	High float64 `json:"high"`
+	// Confidence is Low/High, 1 when the size is known exactly.
+	Confidence float64 `json:"confidence"`
+}
+
+// EstimateSize estimates the size of the ring from the density of ids
+// between the predecessor and the last successor. With k nodes spread over
+// a fraction f of the id space, the ring holds about k/f nodes, give or take
+// a relative error of 1/sqrt(k). When the successor list wraps around the
+// ring, the size is known exactly.
+func (peer *Peer) EstimateSize() SizeEstimate {
+	return peer.network.estimateSize()
+}
+
+func (network *chordNetwork) estimateSize() SizeEstimate {
+	known := len(network.members.alive(network.detector)) + 1
+	return estimateSize(network.self().Id, network.getPredecessor(), network.successors.List(), known)
+}
+
+// EstimateRingSize estimates the size of the ring the node at address is
+// part of, from that node's predecessor and successor list, for tools
+// outside the ring.
+func EstimateRingSize(address string) (estimate SizeEstimate, err error) {
+	network := NewChordNetwork(&ContactInfo{Id: NewEmptyNodeID()})
+	var entry, pred *ContactInfo
+	var successors []*ContactInfo
+	if entry, err = network.Ping(address); err != nil {
+		return
+	}
+	if pred, err = network.Predecessor(entry); err != nil {
+		return
+	}
+	if successors, err = network.SuccessorList(entry); err != nil {
+		return
+	}
+	return estimateSize(entry.Id, pred, successors, 1), nil
+}
+
+// estimateSize estimates the size of the ring around the node with id self,
+// known being the number of nodes the node knows to be alive, itself
+// included.
+func estimateSize(self NodeID, pred *ContactInfo, successors []*ContactInfo, known int) SizeEstimate {
+	start := self
+	gaps := 0
+	if pred != nil && !pred.Id.Equals(self) {
+		start = pred.Id
+		gaps++
+	}
+
+	seen := map[string]bool{}
+	end := self
+	for _, succ := range successors {
+		if succ == nil || succ.Id.IsZero() || seen[succ.Id.String()] {
+			continue
+		}
+		if succ.Id.Equals(self) {
+			// Every node is in the list
+			exact := float64(len(seen) + 1)
+			return SizeEstimate{Size: exact, Low: exact, High: exact, Confidence: 1}
+		}
+		seen[succ.Id.String()] = true
+		end = succ.Id
+		gaps++
+	}
+
+	lower := math.Max(float64(known), float64(gaps+1))
+	density := float64(gaps) / start.ArcFraction(end)
+	spread := 2 / math.Sqrt(float64(gaps))
+
+	estimate := SizeEstimate{
+		Low:  math.Max(lower, density*(1-spread)),
+		High: density * (1 + spread),
+	}
+	estimate.High = math.Max(estimate.High, estimate.Low)
+	estimate.Size = math.Min(math.Max(density, estimate.Low), estimate.High)
+	estimate.Confidence = estimate.Low / estimate.High
+	return estimate
+}
@@ -0,0 +1,74 @@
+package chord
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+// evenNode returns the id of node i of n spread evenly over the ring, off
+// zero, which is no id.
+func evenNode(i, n int) *ContactInfo {
+	id := big.NewInt(0).Lsh(big.NewInt(1), 256)
+	id.Mul(id, big.NewInt(int64(i%n))).Div(id, big.NewInt(int64(n))).Add(id, big.NewInt(1))
+	val := make([]byte, 32)
+	bytes := id.Bytes()
+	copy(val[len(val)-len(bytes):], bytes)
+	return &ContactInfo{Id: NodeID{Val: val}}
+}
+
+// placed returns the network of node i of n, with its predecessor and full
+// successor list set.
+func placed(i, n int) *chordNetwork {
+	network := NewChordNetwork(evenNode(i, n))
+	network.setPredecessor(evenNode(i+n-1, n))
+	for j := 0; j < successorListSize; j++ {
+		network.successors.SetSuccessor(j, evenNode(i+j+1, n))
+	}
+	return network
+}
+
+func TestSizeIsExactWhenSuccessorsWrap(t *testing.T) {
+	// The successor list of a ring of three runs past the node itself
+	estimate := placed(0, 3).estimateSize()
+	if estimate.Size != 3 || estimate.Low != 3 || estimate.High != 3 || estimate.Confidence != 1 {
+		t.Errorf("estimate of a ring of 3 is %+v, expected exactly 3", estimate)
+	}
+}
+
+func TestSizeEstimateFromDensity(t *testing.T) {
+	const n = 40
+	for i := 0; i < n; i += 7 {
+		estimate := placed(i, n).estimateSize()
+		if math.Abs(estimate.Size-n) > 1e-6 {
+			t.Errorf("node %d estimates %v nodes, expected %d", i, estimate.Size, n)
+		}
+		if estimate.Low > n || estimate.High < n || estimate.Confidence >= 1 {
+			t.Errorf("node %d estimates %+v, which does not leave room around %d", i, estimate, n)
+		}
+		// Only the predecessor, the successors and the node are known
+		if estimate.Low < successorListSize+2 {
+			t.Errorf("node %d has a lower bound of %v below the %d nodes it knows", i, estimate.Low, successorListSize+2)
+		}
+	}
+}
+
+func TestEstimateRingSizeThroughEntryNode(t *testing.T) {
+	for _, n := range []int{3, 8} {
+		peers := wiredRing(t, n)
+		entry := peers[0]
+
+		estimate, err := EstimateRingSize(entry.GetInfo().Address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The entry node knows no members beyond its neighbours either
+		if own := entry.EstimateSize(); estimate != own {
+			t.Errorf("ring of %d is estimated at %+v from outside, expected the entry node's own %+v", n, estimate, own)
+		}
+		if estimate.Low > float64(n) || estimate.High < float64(n) {
+			t.Errorf("ring of %d is estimated at %+v", n, estimate)
+		}
+		stopAll(peers)
+	}
+}
@@ -0,0 +1,53 @@
+package chord
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStabilizeAdoptsNeighboursAfterSuccessorFails(t *testing.T) {
+	peers := wiredRing(t, 8)
+	defer stopAll(peers)
+	self, failed, next := peers[0], peers[1], peers[2]
+
+	clock := NewFakeClock(time.Unix(0, 0))
+	self.network.SetClock(clock)
+	next.network.SetClock(clock)
+	failed.Stop()
+
+	// The successor is only replaced once it is dead, not while suspect
+	self.network.Stabilize()
+	next.network.CheckPredecessor()
+	if succ := self.GetSuccessor(); !succ.Id.Equals(failed.GetInfo().Id) {
+		t.Fatalf("successor is %s while the failed one is suspect, expected it to be kept", succ.Address)
+	}
+
+	clock.Advance(DefaultFailureDetectorConfig.SuspectTimeout)
+	next.network.CheckPredecessor()
+	self.network.Stabilize()
+	if succ := self.GetSuccessor(); !succ.Id.Equals(next.GetInfo().Id) {
+		t.Fatalf("successor is %s after the failed one is dead, expected %s", succ.Address, next.GetInfo().Address)
+	}
+	if pred := next.GetPredecessor(); pred == nil || !pred.Id.Equals(self.GetInfo().Id) {
+		t.Fatalf("new successor has predecessor %v, expected to have been notified", pred)
+	}
+
+	// A node joins after the new successor. Stabilizing takes the
+	// successor's list as it is, where walking it would stop at the new
+	// node, which cannot be reached
+	joined := &ContactInfo{Address: "joined", Id: justPast(next.GetInfo().Id)}
+	expected := []*ContactInfo{next.GetInfo(), joined}
+	for i := 3; len(expected) < successorListSize; i++ {
+		expected = append(expected, peers[i].GetInfo())
+	}
+	for i, succ := range expected[1:] {
+		next.network.successors.SetSuccessor(i, succ)
+	}
+
+	self.network.Stabilize()
+	for i, succ := range self.network.successors.List() {
+		if !sameNode(succ, expected[i]) {
+			t.Errorf("successor %d is %s, expected %s", i, succ.Address, expected[i].Address)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package chord
+
+import (
+	"sort"
+	"sync"
+)
+
+type Item struct {
+	Key     string `json:"key"`
+	Value   []byte `json:"value"`
+	Version uint64 `json:"version"`
+}
+
+type dataStore struct {
+	mutex sync.RWMutex
+	items map[string]*Item
+}
+
+func newDataStore() *dataStore {
+	return &dataStore{
+		items: make(map[string]*Item),
+	}
+}
+
+func (store *dataStore) Get(key string) *Item {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	return store.items[key]
+}
+
+// Put only replaces the stored item if the new item has a higher version,
+// so stale writes and read-repairs can be applied in any order.
+func (store *dataStore) Put(item *Item) bool {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	if current, ok := store.items[item.Key]; ok && current.Version >= item.Version {
+		return false
+	}
+	store.items[item.Key] = item
+	return true
+}
+
+func (store *dataStore) Delete(key string) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	delete(store.items, key)
+}
+
+func (store *dataStore) Len() int {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	return len(store.items)
+}
+
+// Items returns every item, ordered by key.
+func (store *dataStore) Items() (items []*Item) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	for _, item := range store.items {
+		items = append(items, item)
+	}
+	sortItems(items)
+	return
+}
+
+func sortItems(items []*Item) {
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Key < items[j].Key
+	})
+}
@@ -1,21 +1,44 @@
 package chord
 
+import "sync"
+
 const successorListSize = 5
 
-type successorList [successorListSize]*ContactInfo
+// successorList is safe for concurrent use, as stabilization changes it
+// while RPCs read it.
+type successorList struct {
+	mutex      sync.RWMutex
+	successors [successorListSize]*ContactInfo
+}
 
 func (successors *successorList) GetSuccessor(i int) *ContactInfo {
-	return successors[i]
+	successors.mutex.RLock()
+	defer successors.mutex.RUnlock()
+	return successors.successors[i]
 }
 
+// SetSuccessor stores info as successor i and returns whether it is a
+// different node than before. A newer contact info of the same node replaces
+// the one held, but returns false.
 func (successors *successorList) SetSuccessor(i int, info *ContactInfo) bool {
-	if info == nil || successors[i] == nil {
-		logger.Error("info %v, succ %v, %d", info, successors[i], i)
+	successors.mutex.Lock()
+	defer successors.mutex.Unlock()
+	if info == nil || successors.successors[i] == nil {
+		logger.Error("info %v, succ %v, %d", info, successors.successors[i], i)
 	}
-	if successors[i] == nil || !info.Id.Equals(successors[i].Id) {
+	if successors.successors[i] == nil || !info.Id.Equals(successors.successors[i].Id) {
 		logger.Info("Setting successor %d to: %s", i, info.Id.String())
-		successors[i] = info
+		successors.successors[i] = info
 		return true
 	}
+	// Same node, but what it advertises may have changed
+	successors.successors[i] = info
 	return false
-}
\ No newline at end of file
+}
+
+// List returns a copy of the successors.
+func (successors *successorList) List() []*ContactInfo {
+	successors.mutex.RLock()
+	defer successors.mutex.RUnlock()
+	return append([]*ContactInfo(nil), successors.successors[:]...)
+}
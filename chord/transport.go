@@ -0,0 +1,32 @@
+package chord
+
+import (
+	"google.golang.org/grpc"
+)
+
+// Transport opens the connections a chordNetwork makes its calls over. The
+// default dials the contact's address over TCP; a simulator can connect
+// nodes in memory instead.
+type Transport interface {
+	Dial(contact *ContactInfo) (*grpc.ClientConn, error)
+}
+
+// grpcTransport dials over TCP, through faults if they are set, which
+// are applied as calls from the address in from.
+type grpcTransport struct {
+	faults *Faults
+	from   string
+}
+
+func (transport grpcTransport) Dial(contact *ContactInfo) (*grpc.ClientConn, error) {
+	unary := targetUnaryInterceptor(contact.Id)
+	stream := targetStreamInterceptor(contact.Id)
+	if transport.faults != nil {
+		unary = chainUnaryClient(transport.faults.UnaryClientInterceptor(transport.from, contact.Address), unary)
+		stream = chainStreamClient(transport.faults.StreamClientInterceptor(transport.from, contact.Address), stream)
+	}
+
+	return grpc.Dial(contact.Address, grpc.WithInsecure(),
+		grpc.WithUnaryInterceptor(unary),
+		grpc.WithStreamInterceptor(stream))
+}
@@ -9,39 +9,81 @@ import (
 var logger = logging.GetLogger()
 
 type tickingFunction struct {
-	timer *time.Timer
+	timer Timer
 	fn    func()
 	stop  chan bool
 	tick  chan bool
+	nudge chan bool
+	done  chan bool
 }
 
-func StartTickingFunction(fn func() int) (tf tickingFunction) {
+func StartTickingFunction(fn func() int) tickingFunction {
+	return StartTickingFunctionWithClock(realClock{}, fn)
+}
+
+// StartTickingFunctionWithClock is StartTickingFunction with its timer taken
+// from clock, so that a FakeClock decides when fn runs.
+func StartTickingFunctionWithClock(clock Clock, fn func() int) (tf tickingFunction) {
 	tf.fn = func() {
-		defer close(tf.stop)
-		defer close(tf.tick)
+		defer close(tf.done)
 		for {
 			select {
-			case <-tf.timer.C:
-				go func() { tf.tick <- true }()
+			case <-tf.timer.C():
+				go tf.Tick()
 			case <-tf.tick:
 				duration := fn()
 				tf.timer.Reset(time.Duration(duration))
+			case <-tf.nudge:
+				duration := fn()
+				tf.timer.Reset(time.Duration(duration))
 			case <-tf.stop:
 				tf.timer.Stop()
 				return
 			}
 		}
 	}
-	tf.timer = time.NewTimer(time.Second)
+	tf.timer = clock.NewTimer(time.Second)
 	tf.stop = make(chan bool)
 	tf.tick = make(chan bool)
+	tf.nudge = make(chan bool, 1)
+	tf.done = make(chan bool)
 
 	go tf.fn()
 	return
 }
 
+// Tick runs the function now instead of waiting for its timer. It blocks
+// until the function has picked the tick up, or has been stopped.
+func (tf tickingFunction) Tick() {
+	select {
+	case tf.tick <- true:
+	case <-tf.done:
+	}
+}
+
+// Nudge is Tick without the wait. A nudge while the function is in the
+// middle of a round runs it once more afterwards, as the round may have
+// missed what the nudge was about.
+func (tf tickingFunction) Nudge() {
+	select {
+	case tf.nudge <- true:
+	default:
+	}
+}
+
+// Stop stops the function for good, after any round in progress.
+func (tf tickingFunction) Stop() {
+	select {
+	case tf.stop <- true:
+	case <-tf.done:
+	}
+}
+
+// cubic eases from min at x = 0 to max at x = 1 and stays at max after
+// that.
 func cubic(min, max float64) (func(x float64) float64) {
 	return func(x float64) float64 {
+		x = math.Max(0, math.Min(x, 1))
 		mu := (1 - math.Cos(x*math.Pi)) / 2
 		return min*(1-mu) + max*mu
 	}
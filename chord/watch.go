@@ -0,0 +1,165 @@
+package chord
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// A subscription to the successor is renewed this often, so it follows the
+// successor when that changes without an update to tell.
+const watchResubscribeInterval = time.Minute
+
+// A subscription that failed is retried after this long.
+const watchRetryInterval = 5 * time.Second
+
+// Neighbours is a node's place in the ring as sent on a watch. Predecessor
+// is nil while the node has none.
+type Neighbours struct {
+	Node        *ContactInfo
+	Predecessor *ContactInfo
+	Successors  []*ContactInfo
+}
+
+func (neighbours Neighbours) equals(other Neighbours) bool {
+	if !sameNode(neighbours.Predecessor, other.Predecessor) || len(neighbours.Successors) != len(other.Successors) {
+		return false
+	}
+	for i := range neighbours.Successors {
+		if !sameNode(neighbours.Successors[i], other.Successors[i]) {
+			return false
+		}
+	}
+	return sameNode(neighbours.Node, other.Node)
+}
+
+func sameNode(a, b *ContactInfo) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Id.Equals(b.Id) && a.Address == b.Address
+}
+
+// watcherSet holds the channels of the watches a node serves. Each channel
+// holds at most the latest update, a watcher that falls behind skips the
+// ones in between rather than holding up the node.
+type watcherSet struct {
+	mutex    sync.Mutex
+	watchers map[chan Neighbours]bool
+	last     Neighbours
+}
+
+func newWatcherSet() *watcherSet {
+	return &watcherSet{watchers: make(map[chan Neighbours]bool)}
+}
+
+// Add starts a watch that receives current first, and is closed when ctx is
+// done.
+func (set *watcherSet) Add(ctx context.Context, current Neighbours) <-chan Neighbours {
+	ch := make(chan Neighbours, 1)
+	ch <- current
+
+	set.mutex.Lock()
+	set.watchers[ch] = true
+	set.mutex.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		set.mutex.Lock()
+		defer set.mutex.Unlock()
+		delete(set.watchers, ch)
+		close(ch)
+	}()
+	return ch
+}
+
+// Publish sends neighbours to every watcher, unless it is what they were
+// sent last.
+func (set *watcherSet) Publish(neighbours Neighbours) {
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+	if neighbours.equals(set.last) {
+		return
+	}
+	set.last = neighbours
+
+	for ch := range set.watchers {
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- neighbours
+	}
+}
+
+// neighbours returns the node's current predecessor and successor list.
+func (network *chordNetwork) neighbours() Neighbours {
+	neighbours := Neighbours{Node: network.self(), Predecessor: network.getPredecessor()}
+	for _, succ := range network.successors.List() {
+		if succ != nil {
+			neighbours.Successors = append(neighbours.Successors, succ)
+		}
+	}
+	return neighbours
+}
+
+// Watch calls fn with every update info sends about its neighbours, until
+// fn returns false, ctx is done or the connection is lost.
+func (network *chordNetwork) Watch(ctx context.Context, info *ContactInfo, fn func(Neighbours) bool) (err error) {
+	if !network.supports(info, CapWatch) {
+		return unsupported(info, "Watch")
+	}
+	err = network.Call(info, func(client ChordClient) error {
+		return client.Watch(ctx, fn)
+	})
+	return
+}
+
+// WatchNode follows the neighbours of the node at address, for tools that
+// want to show the ring as it changes.
+func WatchNode(ctx context.Context, address string, fn func(Neighbours) bool) error {
+	network := NewChordNetwork(&ContactInfo{Id: NewEmptyNodeID()})
+	node, err := network.Ping(address)
+	if err != nil {
+		return err
+	}
+	return network.Watch(ctx, node, fn)
+}
+
+// Watch sends the peer's neighbours, and then every change to them, on the
+// returned channel until ctx is done.
+func (peer *Peer) Watch(ctx context.Context) (<-chan Neighbours, error) {
+	logger.Debug("Watch")
+	return peer.network.watchers.Add(ctx, peer.network.neighbours()), nil
+}
+
+// followSuccessor watches the successor and stabilizes as soon as it has a
+// predecessor other than us, which means a node joined in between, rather
+// than at the next stabilize round.
+func (peer *Peer) followSuccessor(ctx context.Context) {
+	for ctx.Err() == nil {
+		succ := peer.GetSuccessor()
+		if succ.Id.Equals(peer.GetInfo().Id) || !peer.network.supports(succ, CapWatch) {
+			wait(ctx, watchRetryInterval)
+			continue
+		}
+
+		watchCtx, cancel := context.WithTimeout(ctx, watchResubscribeInterval)
+		err := peer.network.Watch(watchCtx, succ, func(neighbours Neighbours) bool {
+			if !peer.GetSuccessor().Id.Equals(succ.Id) {
+				return false
+			}
+			if neighbours.Predecessor == nil || !neighbours.Predecessor.Id.Equals(peer.GetInfo().Id) {
+				peer.Poke()
+			}
+			return true
+		})
+		renew := watchCtx.Err() != nil
+		cancel()
+
+		if err != nil && !renew {
+			logger.Debug("watch on successor %s ended: %v", succ.Address, err)
+			wait(ctx, watchRetryInterval)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package chord
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWatchFiresOnResponsibilityChange(t *testing.T) {
+	peers := wiredRing(t, 3)
+	defer stopAll(peers)
+	watched := peers[1]
+
+	updates := make(chan Neighbours, 8)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go WatchNode(ctx, watched.GetInfo().Address, func(neighbours Neighbours) bool {
+		updates <- neighbours
+		return true
+	})
+
+	next := func() Neighbours {
+		select {
+		case neighbours := <-updates:
+			return neighbours
+		case <-time.After(5 * time.Second):
+			t.Fatal("no update from the watch")
+		}
+		return Neighbours{}
+	}
+	if first := next(); !sameNode(first.Predecessor, peers[0].GetInfo()) {
+		t.Fatalf("watch started with predecessor %v, expected %s", first.Predecessor, peers[0].GetInfo().Address)
+	}
+
+	// A node joins in front of the watched one and takes over part of its
+	// keys
+	joined := &ContactInfo{Address: "joined", Id: justPast(peers[0].GetInfo().Id)}
+	if err := watched.Notify(context.Background(), joined); err != nil {
+		t.Fatal(err)
+	}
+	if update := next(); !sameNode(update.Predecessor, joined) {
+		t.Errorf("watch sent predecessor %v, expected the joined node", update.Predecessor)
+	}
+
+	// A notification from the same predecessor changes nothing to send
+	if err := watched.Notify(context.Background(), joined); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case update := <-updates:
+		t.Errorf("watch sent an update without a change: %+v", update)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,76 @@
+package chordtest
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/lukaspj/go-chord/chord"
+)
+
+// RingIsConsistent checks the ring the way `chord check` does, walking it
+// over RPC from the first running peer, and also checks that the walk found
+// every running peer.
+func RingIsConsistent(t testing.TB, ring *Ring) {
+	t.Helper()
+
+	running := ring.Running()
+	if len(running) == 0 {
+		t.Errorf("ring has no running peers")
+		return
+	}
+
+	nodes, problems, err := chord.CheckRing(running[0].GetInfo().Address)
+	if err != nil {
+		t.Errorf("could not check ring: %v", err)
+		return
+	}
+	for _, problem := range problems {
+		t.Errorf("inconsistent ring: %s", problem)
+	}
+	if len(nodes) != len(running) {
+		t.Errorf("walking the ring found %d nodes, %d are running", len(nodes), len(running))
+	}
+}
+
+// LookupsAgree looks the hash of every key up through every running peer,
+// and checks that all of them find the peer that owns it.
+func LookupsAgree(t testing.TB, ring *Ring, keys ...string) {
+	t.Helper()
+
+	for _, key := range keys {
+		id := chord.NewNodeIDFromHash(key)
+		expected := ring.Responsible(id).GetInfo()
+		for _, peer := range ring.Running() {
+			found, err := peer.FindSuccessor(context.Background(), &id)
+			switch {
+			case err != nil:
+				t.Errorf("lookup of %q through %s failed: %v", key, peer.GetInfo().Address, err)
+			case found == nil:
+				t.Errorf("lookup of %q through %s found nothing, expected %s", key, peer.GetInfo().Address, expected.Address)
+			case found.Address != expected.Address:
+				t.Errorf("lookup of %q through %s found %s, expected %s", key, peer.GetInfo().Address, found.Address, expected.Address)
+			}
+		}
+	}
+}
+
+// AllKeysPresent reads every key back through every running peer, with the
+// peer's own quorum, and checks that it has the expected value.
+func AllKeysPresent(t testing.TB, ring *Ring, items map[string][]byte) {
+	t.Helper()
+
+	for key, value := range items {
+		for _, peer := range ring.Running() {
+			item, err := peer.Get(context.Background(), key)
+			switch {
+			case err != nil:
+				t.Errorf("read of %q through %s failed: %v", key, peer.GetInfo().Address, err)
+			case item == nil:
+				t.Errorf("%q is missing when read through %s", key, peer.GetInfo().Address)
+			case !bytes.Equal(item.Value, value):
+				t.Errorf("%q read through %s is %q, expected %q", key, peer.GetInfo().Address, item.Value, value)
+			}
+		}
+	}
+}
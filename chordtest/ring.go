@@ -0,0 +1,223 @@
+// Package chordtest starts rings of real peers on loopback ports for
+// integration tests, and checks them for the properties a converged ring
+// must have.
+package chordtest
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lukaspj/go-chord/chord"
+)
+
+// pollInterval is how often WaitForConvergence looks at the ring.
+const pollInterval = 50 * time.Millisecond
+
+// Ring is a set of peers in this process, each serving on its own loopback
+// port.
+type Ring struct {
+	Peers   []*chord.Peer
+	stopped map[*chord.Peer]bool
+}
+
+// Start starts n peers on free loopback ports and joins them through the
+// first one. configure, if not nil, is called on every peer before it
+// starts, to change its settings.
+func Start(n int, configure func(peer *chord.Peer)) (*Ring, error) {
+	ring := &Ring{stopped: make(map[*chord.Peer]bool)}
+	for i := 0; i < n; i++ {
+		peer, err := ring.add(configure)
+		if err != nil {
+			ring.Stop()
+			return nil, err
+		}
+		if i > 0 {
+			if err = peer.Connect(ring.Peers[0].GetInfo().Address); err != nil {
+				ring.Stop()
+				return nil, fmt.Errorf("peer %d failed to join: %v", i, err)
+			}
+		}
+	}
+	return ring, nil
+}
+
+// Add starts one more peer and joins it to the ring through a running peer.
+func (ring *Ring) Add(configure func(peer *chord.Peer)) (*chord.Peer, error) {
+	running := ring.Running()
+	peer, err := ring.add(configure)
+	if err != nil {
+		return nil, err
+	}
+	if len(running) > 0 {
+		err = peer.Connect(running[0].GetInfo().Address)
+	}
+	return peer, err
+}
+
+func (ring *Ring) add(configure func(peer *chord.Peer)) (*chord.Peer, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	address := l.Addr().String()
+	info := &chord.ContactInfo{
+		Address: address,
+		Id:      chord.NewNodeIDFromHash(address),
+	}
+	p := chord.NewPeer(info, l.Addr().(*net.TCPAddr).Port)
+	peer := &p
+	if configure != nil {
+		configure(peer)
+	}
+	peer.Serve(l)
+	peer.Start()
+
+	ring.Peers = append(ring.Peers, peer)
+	return peer, nil
+}
+
+// Fail stops peer without telling anyone, like a crash.
+func (ring *Ring) Fail(peer *chord.Peer) {
+	if !ring.stopped[peer] {
+		ring.stopped[peer] = true
+		peer.Stop()
+	}
+}
+
+// Restart brings a failed peer back on its own address, with the items it
+// stored but the routing state of a new node, and joins it to the ring
+// through a running peer.
+func (ring *Ring) Restart(peer *chord.Peer) error {
+	if !ring.stopped[peer] {
+		return fmt.Errorf("%s is running", peer.GetInfo().Address)
+	}
+	running := ring.Running()
+
+	l, err := net.Listen("tcp", peer.GetInfo().Address)
+	if err != nil {
+		return err
+	}
+	peer.Serve(l)
+	peer.Start()
+	delete(ring.stopped, peer)
+
+	if len(running) > 0 {
+		err = peer.Connect(running[0].GetInfo().Address)
+	}
+	return err
+}
+
+// Stop stops every peer in the ring.
+func (ring *Ring) Stop() {
+	for _, peer := range ring.Peers {
+		ring.Fail(peer)
+	}
+}
+
+// Running returns the peers that have not been stopped, ordered by id.
+func (ring *Ring) Running() (peers []*chord.Peer) {
+	for _, peer := range ring.Peers {
+		if !ring.stopped[peer] {
+			peers = append(peers, peer)
+		}
+	}
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].GetInfo().Id.Less(peers[j].GetInfo().Id)
+	})
+	return
+}
+
+// Responsible returns the running peer that should own id.
+func (ring *Ring) Responsible(id chord.NodeID) *chord.Peer {
+	peers := ring.Running()
+	i := sort.Search(len(peers), func(i int) bool {
+		return !peers[i].GetInfo().Id.Less(id)
+	})
+	return peers[i%len(peers)]
+}
+
+// Problems compares the successor, predecessor and successor list of every
+// running peer with the ring the running peers should form. It returns
+// nothing once the ring has converged.
+func (ring *Ring) Problems() (problems []string) {
+	peers := ring.Running()
+	n := len(peers)
+	for i, peer := range peers {
+		next := peers[(i+1)%n].GetInfo()
+		prev := peers[(i+n-1)%n].GetInfo()
+
+		if succ := peer.GetSuccessor(); !same(succ, next) {
+			problems = append(problems, fmt.Sprintf("%s: successor is %s, expected %s", peer.GetInfo().Address, address(succ), next.Address))
+		}
+		if pred := peer.GetPredecessor(); !same(pred, prev) {
+			problems = append(problems, fmt.Sprintf("%s: predecessor is %s, expected %s", peer.GetInfo().Address, address(pred), prev.Address))
+		}
+
+		list, _ := peer.SuccessorList(context.Background())
+		for j := 0; j < len(list) && j < n-1; j++ {
+			expected := peers[(i+1+j)%n].GetInfo()
+			if !same(list[j], expected) {
+				problems = append(problems, fmt.Sprintf("%s: successor %d is %s, expected %s", peer.GetInfo().Address, j, address(list[j]), expected.Address))
+				break
+			}
+		}
+	}
+	return
+}
+
+// WaitForConvergence polls the running peers until Problems reports
+// nothing and checking the ring finds every finger in place, or returns the
+// problems that were left when timeout ran out.
+func (ring *Ring) WaitForConvergence(timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		problems := ring.Problems()
+		if len(problems) == 0 {
+			problems = ring.checkProblems()
+		}
+		if len(problems) == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("ring did not converge within %v:\n%s", timeout, strings.Join(problems, "\n"))
+		}
+		for _, peer := range ring.Running() {
+			peer.Poke()
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// checkProblems walks the ring the way `chord check` does, which also
+// compares the fingers fix-fingers fills in after the successors settle.
+func (ring *Ring) checkProblems() (problems []string) {
+	running := ring.Running()
+	if len(running) == 0 {
+		return
+	}
+	_, inconsistencies, err := chord.CheckRing(running[0].GetInfo().Address)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	for _, inconsistency := range inconsistencies {
+		problems = append(problems, inconsistency.String())
+	}
+	return
+}
+
+// same tells whether info is node, under its current id.
+func same(info, node *chord.ContactInfo) bool {
+	return info != nil && info.Address == node.Address && info.Id.Equals(node.Id)
+}
+
+func address(info *chord.ContactInfo) string {
+	if info == nil {
+		return "<none>"
+	}
+	return info.Address
+}
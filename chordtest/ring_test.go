@@ -0,0 +1,45 @@
+package chordtest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestRingConverges(t *testing.T) {
+	ring, err := Start(6, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ring.Stop()
+	if err = ring.WaitForConvergence(30 * time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	items := make(map[string][]byte)
+	var keys []string
+	for i := 0; i < 10; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		keys = append(keys, key)
+		items[key] = []byte(fmt.Sprintf("value-%d", i))
+		if err = ring.Peers[i%len(ring.Peers)].Put(context.Background(), key, items[key]); err != nil {
+			t.Fatalf("put of %s failed: %v", key, err)
+		}
+	}
+
+	RingIsConsistent(t, ring)
+	LookupsAgree(t, ring, keys...)
+	AllKeysPresent(t, ring, items)
+
+	// And again after another peer has joined
+	if _, err = ring.Add(nil); err != nil {
+		t.Fatal(err)
+	}
+	if err = ring.WaitForConvergence(30 * time.Second); err != nil {
+		t.Fatal(err)
+	}
+	RingIsConsistent(t, ring)
+	LookupsAgree(t, ring, keys...)
+	AllKeysPresent(t, ring, items)
+}
@@ -1,8 +1,12 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"os"
+	"sort"
+	"strings"
 	"github.com/lukaspj/go-logging/logging"
 	"github.com/lukaspj/go-chord/chord"
 )
@@ -13,17 +17,37 @@ func main() {
 	logger.SetLevel(logging.INFO)
 	logger.AddStdoutOutput()
 
+	if len(os.Args) > 1 && os.Args[1] == "check" {
+		os.Exit(check(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "aggregate" {
+		os.Exit(aggregate(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "size" {
+		os.Exit(size(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "find" {
+		os.Exit(find(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "watch" {
+		os.Exit(watch(os.Args[2:]))
+	}
+
 	port := flag.Int("sp", 5600, "Source port")
 	host := flag.String("sh", "127.0.0.1", "Source host")
 	id := flag.String("id", "", "id")
 	dest := flag.String("dest", "", "Destination address")
+	weight := flag.Int("weight", 1, "Number of virtual nodes to host")
+	role := flag.String("role", "", "Role advertised in the node's metadata")
+	zone := flag.String("zone", "", "Zone advertised in the node's metadata")
+	endpoints := flag.String("endpoints", "", "Services advertised in the node's metadata, as name=address,...")
 
 
 	flag.Parse()
 
 	var nid chord.NodeID
 	if *id != "" {
-		if len(*id) == 40 {
+		if len(*id) == 2*chord.IdLength {
 			// Assume hex value
 			nid = chord.NewNodeIDFromString(*id)
 		} else {
@@ -33,18 +57,171 @@ func main() {
 		nid = chord.NewNodeIDFromHash(fmt.Sprintf("%s%d", *host, *port))
 	}
 
+	metadata := chord.Metadata{Role: *role, Zone: *zone}
+	if *endpoints != "" {
+		metadata.Endpoints = make(map[string]string)
+		for _, endpoint := range strings.Split(*endpoints, ",") {
+			parts := strings.SplitN(endpoint, "=", 2)
+			if len(parts) != 2 {
+				fmt.Printf("malformed endpoint %q, expected name=address\n", endpoint)
+				os.Exit(2)
+			}
+			metadata.Endpoints[parts[0]] = parts[1]
+		}
+	}
+
 	info := &chord.ContactInfo{
 		Id: nid,
 		Address: fmt.Sprintf("%s:%d", *host, *port),
+		Payload: metadata.Encode(),
 	}
 
-	peer := chord.NewPeer(info, *port)
+	node := chord.NewHost(info, *port, *weight)
 
-	peer.Listen()
+	if err := node.Listen(); err != nil {
+		fmt.Printf("failed to listen on port %d: %v\n", *port, err)
+		os.Exit(1)
+	}
 
 	if *dest != "" {
-		peer.Connect(*dest)
+		node.Connect(*dest)
 	}
 
 	<-make(chan struct{})
 }
+
+// check walks the ring through the given entry node and reports every
+// inconsistency it finds, returning a non-zero exit code if there are any.
+func check(args []string) int {
+	logger.SetLevel(logging.ERROR)
+
+	flags := flag.NewFlagSet("check", flag.ExitOnError)
+	dest := flags.String("dest", "127.0.0.1:5600", "Address of the entry node")
+	flags.Parse(args)
+
+	nodes, problems, err := chord.CheckRing(*dest)
+	if err != nil {
+		fmt.Printf("failed to check ring through %s: %v\n", *dest, err)
+		return 2
+	}
+
+	fmt.Printf("walked %d nodes\n", len(nodes))
+	for _, problem := range problems {
+		fmt.Println(problem)
+	}
+	if len(problems) > 0 {
+		fmt.Printf("found %d inconsistencies\n", len(problems))
+		return 1
+	}
+	fmt.Println("ring is consistent")
+	return 0
+}
+
+// aggregate prints ring-wide figures collected through the entry node.
+func aggregate(args []string) int {
+	logger.SetLevel(logging.ERROR)
+
+	flags := flag.NewFlagSet("aggregate", flag.ExitOnError)
+	dest := flags.String("dest", "127.0.0.1:5600", "Address of the entry node")
+	flags.Parse(args)
+
+	res, err := chord.AggregateRing(*dest)
+	if err != nil {
+		fmt.Printf("failed to aggregate through %s: %v\n", *dest, err)
+		return 2
+	}
+
+	fmt.Printf("nodes: %d\n", res.Nodes)
+	fmt.Printf("keys: %d\n", res.Keys)
+	fmt.Printf("load: %.2f - %.2f requests/s\n", res.MinLoad, res.MaxLoad)
+	var versions []int
+	for version := range res.Versions {
+		versions = append(versions, int(version))
+	}
+	sort.Ints(versions)
+	for _, version := range versions {
+		fmt.Printf("version %d: %d nodes\n", version, res.Versions[uint32(version)])
+	}
+	for _, node := range res.Unreachable {
+		fmt.Printf("unreachable: %s (%s)\n", node.Id.String(), node.Address)
+	}
+	if len(res.Unreachable) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// size prints the estimated number of nodes in the ring, from the
+// neighbours of the given entry node.
+func size(args []string) int {
+	logger.SetLevel(logging.ERROR)
+
+	flags := flag.NewFlagSet("size", flag.ExitOnError)
+	dest := flags.String("dest", "127.0.0.1:5600", "Address of the entry node")
+	flags.Parse(args)
+
+	estimate, err := chord.EstimateRingSize(*dest)
+	if err != nil {
+		fmt.Printf("failed to estimate the ring size through %s: %v\n", *dest, err)
+		return 2
+	}
+
+	fmt.Printf("size: %.0f\n", estimate.Size)
+	fmt.Printf("range: %.0f - %.0f nodes\n", estimate.Low, estimate.High)
+	fmt.Printf("confidence: %.2f\n", estimate.Confidence)
+	return 0
+}
+
+// find prints the nodes whose metadata matches the name=value terms given
+// after the flags, such as role=storage or endpoint.http.
+func find(args []string) int {
+	logger.SetLevel(logging.ERROR)
+
+	flags := flag.NewFlagSet("find", flag.ExitOnError)
+	dest := flags.String("dest", "127.0.0.1:5600", "Address of the entry node")
+	flags.Parse(args)
+
+	query, err := chord.ParseQuery(flags.Args())
+	if err != nil {
+		fmt.Println(err)
+		return 2
+	}
+	nodes, err := chord.FindRingNodes(*dest, query)
+	if err != nil {
+		fmt.Printf("failed to search through %s: %v\n", *dest, err)
+		return 2
+	}
+
+	for _, node := range nodes {
+		fmt.Printf("%s (%s) %s\n", node.Id.String(), node.Address, node.Payload)
+	}
+	fmt.Printf("found %d nodes\n", len(nodes))
+	return 0
+}
+
+// watch prints the neighbours of a node every time they change.
+func watch(args []string) int {
+	logger.SetLevel(logging.ERROR)
+
+	flags := flag.NewFlagSet("watch", flag.ExitOnError)
+	dest := flags.String("dest", "127.0.0.1:5600", "Address of the node to watch")
+	flags.Parse(args)
+
+	err := chord.WatchNode(context.Background(), *dest, func(neighbours chord.Neighbours) bool {
+		pred := "none"
+		if neighbours.Predecessor != nil {
+			pred = neighbours.Predecessor.Address
+		}
+		fmt.Printf("%s predecessor=%s successors=", neighbours.Node.Address, pred)
+		for i, succ := range neighbours.Successors {
+			if i > 0 {
+				fmt.Print(",")
+			}
+			fmt.Print(succ.Address)
+		}
+		fmt.Println()
+		return true
+	})
+	fmt.Printf("stopped watching %s: %v\n", *dest, err)
+	return 1
+}
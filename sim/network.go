@@ -0,0 +1,149 @@
+package sim
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/lukaspj/go-chord/chord"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const listenerBufferSize = 256 * 1024
+
+// Network connects simulated nodes in memory. Whether a call gets through is
+// decided when it is made, from which nodes are up, how they are
+// partitioned and the configured message loss.
+type Network struct {
+	mutex     sync.Mutex
+	clock     *chord.FakeClock
+	random    *rand.Rand
+	listeners map[string]*bufconn.Listener
+	groups    map[string]int
+	loss      float64
+	latency   time.Duration
+	trace     func(entry string)
+}
+
+func newNetwork(clock *chord.FakeClock, random *rand.Rand) *Network {
+	return &Network{
+		clock:     clock,
+		random:    random,
+		listeners: make(map[string]*bufconn.Listener),
+		groups:    make(map[string]int),
+	}
+}
+
+func (network *Network) listen(address string) net.Listener {
+	network.mutex.Lock()
+	defer network.mutex.Unlock()
+
+	l := bufconn.Listen(listenerBufferSize)
+	network.listeners[address] = l
+	return l
+}
+
+func (network *Network) close(address string) {
+	network.mutex.Lock()
+	defer network.mutex.Unlock()
+
+	if l, ok := network.listeners[address]; ok {
+		l.Close()
+		delete(network.listeners, address)
+	}
+}
+
+// deliver decides whether a message from one address to another arrives.
+func (network *Network) deliver(from, to string) error {
+	network.mutex.Lock()
+	defer network.mutex.Unlock()
+
+	if _, ok := network.listeners[to]; !ok {
+		return status.Errorf(codes.Unavailable, "%s is down", to)
+	}
+	if network.groups[from] != network.groups[to] {
+		return status.Errorf(codes.Unavailable, "%s and %s are partitioned", from, to)
+	}
+	if network.loss > 0 && network.random.Float64() < network.loss {
+		return status.Errorf(codes.Unavailable, "message from %s to %s was lost", from, to)
+	}
+	return nil
+}
+
+// record passes a call and its outcome to the trace, if there is one.
+func (network *Network) record(from, to, method string, err error) {
+	network.mutex.Lock()
+	trace := network.trace
+	network.mutex.Unlock()
+	if trace == nil {
+		return
+	}
+
+	outcome := "ok"
+	if err != nil {
+		outcome = status.Code(err).String()
+	}
+	trace(fmt.Sprintf("%v %s -> %s %s %s", network.clock.Now().Sub(epoch), from, to, method, outcome))
+}
+
+// transport is the chord.Transport of a single node, which knows who is
+// calling so partitions can be applied.
+type transport struct {
+	network *Network
+	from    string
+}
+
+func (t *transport) Dial(contact *chord.ContactInfo) (*grpc.ClientConn, error) {
+	to := contact.Address
+	return grpc.Dial(to, grpc.WithInsecure(),
+		grpc.WithDialer(func(string, time.Duration) (net.Conn, error) {
+			t.network.mutex.Lock()
+			l, ok := t.network.listeners[to]
+			t.network.mutex.Unlock()
+			if !ok {
+				return nil, fmt.Errorf("%s is down", to)
+			}
+			return l.Dial()
+		}),
+		grpc.WithUnaryInterceptor(t.unary(to)),
+		grpc.WithStreamInterceptor(t.stream(to)))
+}
+
+// unary delivers the request and the reply separately, so a lost reply can
+// leave the callee changed while the caller sees a failure.
+func (t *transport) unary(to string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) (err error) {
+		defer func() { t.network.record(t.from, to, method, err) }()
+
+		if err = t.network.deliver(t.from, to); err != nil {
+			return
+		}
+		t.network.clock.Advance(t.network.latency)
+		if err = invoker(ctx, method, req, reply, cc, opts...); err != nil {
+			return
+		}
+		if err = t.network.deliver(to, t.from); err != nil {
+			return
+		}
+		t.network.clock.Advance(t.network.latency)
+		return
+	}
+}
+
+func (t *transport) stream(to string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		err := t.network.deliver(t.from, to)
+		t.network.record(t.from, to, method, err)
+		if err != nil {
+			return nil, err
+		}
+		t.network.clock.Advance(2 * t.network.latency)
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+}
@@ -0,0 +1,269 @@
+// Package sim runs many chord peers against a virtual clock and an in-memory
+// network. Maintenance rounds, scripted events and message loss are driven
+// from a single seed, and the peers do their work one call at a time, so a
+// failing churn scenario can be replayed exactly.
+package sim
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/lukaspj/go-chord/chord"
+)
+
+// epoch is where virtual time starts, so that runs do not depend on the
+// wall clock.
+var epoch = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// Node is a simulated peer.
+type Node struct {
+	Name string
+	Peer *chord.Peer
+	up   bool
+}
+
+func (node *Node) Up() bool {
+	return node.up
+}
+
+type Simulator struct {
+	seed    int64
+	clock   *chord.FakeClock
+	random  *rand.Rand
+	network *Network
+	events  eventQueue
+	seq     uint64
+	nodes   map[string]*Node
+}
+
+func New(seed int64) *Simulator {
+	clock := chord.NewFakeClock(epoch)
+	return &Simulator{
+		seed:    seed,
+		clock:   clock,
+		random:  rand.New(rand.NewSource(seed)),
+		network: newNetwork(clock, rand.New(rand.NewSource(seed+1))),
+		nodes:   make(map[string]*Node),
+	}
+}
+
+func (sim *Simulator) Clock() *chord.FakeClock {
+	return sim.clock
+}
+
+func (sim *Simulator) Now() time.Time {
+	return sim.clock.Now()
+}
+
+// At schedules fn to run after d of virtual time, which is how scenarios
+// are scripted.
+func (sim *Simulator) At(d time.Duration, fn func()) {
+	sim.schedule(sim.clock.Now().Add(d), fn)
+}
+
+func (sim *Simulator) schedule(at time.Time, fn func()) {
+	sim.seq++
+	heap.Push(&sim.events, &event{at: at, seq: sim.seq, fn: fn})
+}
+
+// Run processes every event due in the next d of virtual time, one at a
+// time and in order.
+func (sim *Simulator) Run(d time.Duration) {
+	end := sim.clock.Now().Add(d)
+	for len(sim.events) > 0 && !sim.events[0].at.After(end) {
+		next := heap.Pop(&sim.events).(*event)
+		sim.clock.Set(next.at)
+		next.fn()
+	}
+	sim.clock.Set(end)
+}
+
+// SetLoss sets the probability that a request or a reply is lost.
+func (sim *Simulator) SetLoss(p float64) {
+	sim.network.mutex.Lock()
+	defer sim.network.mutex.Unlock()
+	sim.network.loss = p
+}
+
+// SetLatency sets the one-way delay of every message.
+func (sim *Simulator) SetLatency(d time.Duration) {
+	sim.network.mutex.Lock()
+	defer sim.network.mutex.Unlock()
+	sim.network.latency = d
+}
+
+// Trace calls fn with a line for every message sent from now on, giving
+// the virtual time, the nodes, the method and the outcome. Two runs of a
+// scenario with the same seed give the same lines.
+func (sim *Simulator) Trace(fn func(entry string)) {
+	sim.network.mutex.Lock()
+	defer sim.network.mutex.Unlock()
+	sim.network.trace = fn
+}
+
+// Partition splits the nodes into groups that cannot reach each other.
+// Nodes not named in any group form a group of their own.
+func (sim *Simulator) Partition(groups ...[]string) {
+	sim.network.mutex.Lock()
+	defer sim.network.mutex.Unlock()
+
+	sim.network.groups = make(map[string]int)
+	for i, group := range groups {
+		for _, name := range group {
+			sim.network.groups[name] = i + 1
+		}
+	}
+}
+
+// Heal removes every partition.
+func (sim *Simulator) Heal() {
+	sim.Partition()
+}
+
+// Join starts a node called name and joins it to the ring through the node
+// called via, or starts a new ring if via is empty. The node's ID is the
+// hash of its name.
+func (sim *Simulator) Join(name, via string) (*Node, error) {
+	if _, ok := sim.nodes[name]; ok {
+		return nil, fmt.Errorf("node %s already exists", name)
+	}
+
+	info := &chord.ContactInfo{
+		Address: name,
+		Id:      chord.NewNodeIDFromHash(name),
+	}
+	p := chord.NewPeer(info, 0)
+	peer := &p
+	peer.Transport = &transport{network: sim.network, from: name}
+	peer.Clock = sim.clock
+	peer.Random = rand.New(rand.NewSource(sim.seed ^ int64(sim.random.Uint64()))).Intn
+	peer.Sequential = true
+	peer.Reset()
+	peer.Serve(sim.network.listen(name))
+
+	node := &Node{Name: name, Peer: peer, up: true}
+	sim.nodes[name] = node
+
+	if via != "" {
+		if err := peer.Connect(via); err != nil {
+			return node, err
+		}
+	}
+
+	for _, task := range peer.MaintenanceTasks() {
+		jitter := time.Duration(sim.random.Int63n(int64(time.Second)))
+		sim.every(node, task, jitter)
+	}
+	return node, nil
+}
+
+// every runs task on node after d and then at the intervals the task
+// asks for, for as long as the node is up.
+func (sim *Simulator) every(node *Node, task chord.MaintenanceTask, d time.Duration) {
+	sim.At(d, func() {
+		if !node.up {
+			return
+		}
+		sim.every(node, task, time.Duration(task.Run()))
+	})
+}
+
+// Fail crashes the node called name. It stops answering at once and its
+// maintenance stops.
+func (sim *Simulator) Fail(name string) {
+	if node, ok := sim.nodes[name]; ok && node.up {
+		node.up = false
+		sim.network.close(name)
+	}
+}
+
+func (sim *Simulator) Node(name string) *Node {
+	return sim.nodes[name]
+}
+
+// Nodes returns the nodes that are up, ordered by ID.
+func (sim *Simulator) Nodes() (nodes []*Node) {
+	for _, node := range sim.nodes {
+		if node.up {
+			nodes = append(nodes, node)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Peer.GetInfo().Id.Less(nodes[j].Peer.GetInfo().Id)
+	})
+	return
+}
+
+// Lookup resolves id from the node called from.
+func (sim *Simulator) Lookup(from string, id chord.NodeID) (*chord.ContactInfo, error) {
+	node, ok := sim.nodes[from]
+	if !ok || !node.up {
+		return nil, fmt.Errorf("node %s is not up", from)
+	}
+	return node.Peer.FindSuccessor(context.Background(), &id)
+}
+
+// Inconsistencies compares the successor and predecessor of every node that
+// is up with the ring those nodes should form. It is empty once the ring
+// has converged.
+func (sim *Simulator) Inconsistencies() (problems []string) {
+	nodes := sim.Nodes()
+	for i, node := range nodes {
+		next := nodes[(i+1)%len(nodes)].Peer.GetInfo()
+		prev := nodes[(i+len(nodes)-1)%len(nodes)].Peer.GetInfo()
+
+		succ := node.Peer.GetSuccessor()
+		if succ == nil || succ.Address != next.Address {
+			problems = append(problems, fmt.Sprintf("%s: successor is %s, expected %s", node.Name, address(succ), next.Address))
+		}
+		pred := node.Peer.GetPredecessor()
+		if pred == nil || pred.Address != prev.Address {
+			problems = append(problems, fmt.Sprintf("%s: predecessor is %s, expected %s", node.Name, address(pred), prev.Address))
+		}
+	}
+	return
+}
+
+func address(info *chord.ContactInfo) string {
+	if info == nil {
+		return "<none>"
+	}
+	return info.Address
+}
+
+type event struct {
+	at  time.Time
+	seq uint64
+	fn  func()
+}
+
+// eventQueue orders events by time, and events at the same time in the order
+// they were scheduled.
+type eventQueue []*event
+
+func (q eventQueue) Len() int { return len(q) }
+
+func (q eventQueue) Less(i, j int) bool {
+	if q[i].at.Equal(q[j].at) {
+		return q[i].seq < q[j].seq
+	}
+	return q[i].at.Before(q[j].at)
+}
+
+func (q eventQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *eventQueue) Push(x interface{}) {
+	*q = append(*q, x.(*event))
+}
+
+func (q *eventQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	e := old[n-1]
+	*q = old[:n-1]
+	return e
+}
@@ -0,0 +1,182 @@
+package sim
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lukaspj/go-chord/chord"
+)
+
+// scenario joins a ring under message loss, stores and publishes through
+// it and crashes a node, and returns the messages that were sent.
+func scenario(t *testing.T, seed int64) (trace []string) {
+	sim := New(seed)
+	sim.Trace(func(entry string) {
+		trace = append(trace, entry)
+	})
+	sim.SetLatency(5 * time.Millisecond)
+
+	for i := 0; i < 8; i++ {
+		via := ""
+		if i > 0 {
+			via = "n0"
+		}
+		if _, err := sim.Join(fmt.Sprintf("n%d", i), via); err != nil {
+			t.Fatal(err)
+		}
+		sim.Run(5 * time.Second)
+	}
+	sim.SetLoss(0.02)
+	sim.Run(time.Minute)
+
+	ctx := context.Background()
+	sim.At(0, func() {
+		peer := sim.Node("n1").Peer
+		peer.Subscribe(ctx, "topic", func(ctx context.Context, topic string, payload []byte) {})
+		for i := 0; i < 5; i++ {
+			peer.Put(ctx, fmt.Sprintf("key-%d", i), []byte("value"))
+		}
+	})
+	sim.At(10*time.Second, func() {
+		peer := sim.Node("n2").Peer
+		peer.Publish(ctx, "topic", []byte("message"))
+		peer.Broadcast(ctx, []byte("broadcast"), false)
+		peer.Aggregate(ctx)
+	})
+	sim.At(20*time.Second, func() {
+		sim.Fail("n3")
+	})
+	sim.At(30*time.Second, func() {
+		peer := sim.Node("n4").Peer
+		for i := 0; i < 5; i++ {
+			peer.Get(ctx, fmt.Sprintf("key-%d", i))
+		}
+	})
+	sim.Run(2 * time.Minute)
+	return
+}
+
+func TestSameSeedReplaysRun(t *testing.T) {
+	first := scenario(t, 42)
+	second := scenario(t, 42)
+
+	if len(first) == 0 {
+		t.Fatal("no messages were traced")
+	}
+	for i := 0; i < len(first) && i < len(second); i++ {
+		if first[i] != second[i] {
+			t.Fatalf("runs diverge at message %d:\n%s\n%s", i, first[i], second[i])
+		}
+	}
+	if len(first) != len(second) {
+		t.Fatalf("first run sent %d messages, second %d", len(first), len(second))
+	}
+}
+
+// A partition that outlasts the membership tombstones leaves each side with
+// only dead or former members of the other to probe, and those are enough
+// to merge the two rings once the network heals.
+func TestRingsMergeAfterLongPartition(t *testing.T) {
+	sim := New(7)
+	sim.SetLatency(5 * time.Millisecond)
+
+	// n4 is the only node whose seed is across the partition, and it fails
+	// while the network is split
+	joins := [][2]string{
+		{"n0", ""}, {"n1", "n0"}, {"n2", "n0"}, {"n3", "n0"},
+		{"n4", "n0"}, {"n5", "n4"}, {"n6", "n4"}, {"n7", "n4"},
+	}
+	for _, join := range joins {
+		if _, err := sim.Join(join[0], join[1]); err != nil {
+			t.Fatal(err)
+		}
+		sim.Run(5 * time.Second)
+	}
+	sim.Run(time.Minute)
+	if problems := sim.Inconsistencies(); len(problems) > 0 {
+		t.Fatalf("ring did not converge before the partition: %v", problems)
+	}
+
+	sim.Partition([]string{"n0", "n1", "n2", "n3"}, []string{"n4", "n5", "n6", "n7"})
+	sim.Run(time.Minute)
+	sim.Fail("n4")
+	sim.Run(5 * time.Minute)
+	if problems := sim.Inconsistencies(); len(problems) == 0 {
+		t.Fatal("the partitioned halves still form one ring")
+	}
+
+	sim.Heal()
+	sim.Run(5 * time.Minute)
+	if problems := sim.Inconsistencies(); len(problems) > 0 {
+		t.Errorf("rings did not merge after the partition healed: %v", problems)
+	}
+}
+
+// Churn under message loss, with nodes failing and joining at scripted
+// times, must leave a single ring in which every node resolves lookups to
+// the same responsible node.
+func TestRingConvergesAfterChurn(t *testing.T) {
+	sim := New(11)
+	sim.SetLatency(5 * time.Millisecond)
+
+	for i := 0; i < 10; i++ {
+		via := ""
+		if i > 0 {
+			via = "n0"
+		}
+		if _, err := sim.Join(fmt.Sprintf("n%d", i), via); err != nil {
+			t.Fatal(err)
+		}
+		sim.Run(5 * time.Second)
+	}
+	// Nothing pokes the maintenance of simulated nodes when they are
+	// notified, so the first node, backed off while it was alone, takes
+	// minutes to take a successor
+	sim.Run(10 * time.Minute)
+	if problems := sim.Inconsistencies(); len(problems) > 0 {
+		t.Fatalf("ring did not converge before the churn: %v", problems)
+	}
+
+	sim.SetLoss(0.01)
+	for i, name := range []string{"n2", "n5", "n8"} {
+		name := name
+		sim.At(time.Duration(i)*10*time.Second, func() {
+			sim.Fail(name)
+		})
+	}
+	for i := 10; i < 13; i++ {
+		name := fmt.Sprintf("n%d", i)
+		sim.At(time.Duration(i-10)*10*time.Second+5*time.Second, func() {
+			if _, err := sim.Join(name, "n1"); err != nil {
+				t.Errorf("%s failed to join: %v", name, err)
+			}
+		})
+	}
+	sim.Run(time.Minute)
+	sim.SetLoss(0)
+	sim.Run(5 * time.Minute)
+
+	if problems := sim.Inconsistencies(); len(problems) > 0 {
+		t.Fatalf("ring did not converge after the churn: %v", problems)
+	}
+	if nodes := sim.Nodes(); len(nodes) != 10 {
+		t.Fatalf("%d nodes are up, expected 10", len(nodes))
+	}
+	for i := 0; i < 20; i++ {
+		id := chord.NewNodeIDFromHash(fmt.Sprintf("churn-%d", i))
+		var expected string
+		for _, node := range sim.Nodes() {
+			found, err := sim.Lookup(node.Name, id)
+			if err != nil {
+				t.Fatalf("lookup of %s from %s failed: %v", id.String(), node.Name, err)
+			}
+			if expected == "" {
+				expected = found.Address
+			} else if found.Address != expected {
+				t.Errorf("lookup of %s from %s found %s, from others %s", id.String(), node.Name, found.Address, expected)
+			}
+		}
+	}
+}